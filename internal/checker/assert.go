@@ -0,0 +1,208 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/luizhreis/domain-watcher/internal/jsonpath"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// maxExcerpt limita o trecho do corpo registrado em AssertionFailure.Actual
+const maxExcerpt = 64
+
+// bodyBuffer guarda até limit bytes do corpo e descarta o restante, para que
+// a leitura continue medindo o tamanho total da resposta. truncated indica
+// que algum byte foi descartado.
+type bodyBuffer struct {
+	data      []byte
+	limit     int64
+	truncated bool
+}
+
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	room := max(b.limit-int64(len(b.data)), 0)
+	if int64(len(p)) > room {
+		b.truncated = true
+	}
+	b.data = append(b.data, p[:min(int64(len(p)), room)]...)
+	return len(p), nil
+}
+
+// evaluateAssertions avalia a resposta final contra as asserções do domínio.
+// As asserções de corpo consideram apenas os bytes guardados em buf; quando o
+// corpo foi truncado, as que dependem do trecho descartado falham informando
+// o limite. size é o tamanho total lido e latency, o ResponseTime em
+// milissegundos.
+func evaluateAssertions(a *models.Assertions, resp *http.Response, buf *bodyBuffer, size, latency int64) []models.AssertionFailure {
+	if a == nil {
+		return nil
+	}
+	body := buf.data
+
+	// Um trecho ausente ou uma expressão não satisfeita no início do corpo
+	// ainda pode aparecer no restante descartado
+	unseen := excerpt(body)
+	if buf.truncated {
+		unseen = fmt.Sprintf("body exceeds %d bytes; the rest was not evaluated", buf.limit)
+	}
+
+	var failures []models.AssertionFailure
+	fail := func(assertion, expected, actual string) {
+		failures = append(failures, models.AssertionFailure{Assertion: assertion, Expected: expected, Actual: actual})
+	}
+
+	if len(a.StatusCodes) > 0 && !slices.Contains(a.StatusCodes, resp.StatusCode) {
+		fail(models.AssertionStatusCodes, fmt.Sprint(a.StatusCodes), strconv.Itoa(resp.StatusCode))
+	}
+
+	for _, s := range a.BodyContains {
+		if !bytes.Contains(body, []byte(s)) {
+			fail(models.AssertionBodyContains, s, unseen)
+		}
+	}
+	for _, s := range a.BodyNotContains {
+		if i := bytes.Index(body, []byte(s)); i >= 0 {
+			fail(models.AssertionBodyNotContains, s, excerpt(body[i:]))
+		} else if buf.truncated {
+			fail(models.AssertionBodyNotContains, s, unseen)
+		}
+	}
+
+	// As expressões são validadas ao gravar o domínio; uma inválida aqui
+	// só pode vir de dados gravados por fora do serviço
+	for _, expr := range a.BodyMatches {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			fail(models.AssertionBodyMatches, expr, "invalid expression: "+err.Error())
+			continue
+		}
+		if !re.Match(body) {
+			fail(models.AssertionBodyMatches, expr, unseen)
+		}
+	}
+	for _, expr := range a.BodyNotMatches {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			fail(models.AssertionBodyNotMatches, expr, "invalid expression: "+err.Error())
+			continue
+		}
+		if loc := re.FindIndex(body); loc != nil {
+			fail(models.AssertionBodyNotMatches, expr, excerpt(body[loc[0]:loc[1]]))
+		} else if buf.truncated {
+			fail(models.AssertionBodyNotMatches, expr, unseen)
+		}
+	}
+
+	if len(a.JSON) > 0 {
+		failures = append(failures, evaluateJSON(a.JSON, buf)...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(a.Headers)) {
+		expected := a.Headers[name]
+		values := resp.Header.Values(name)
+		switch {
+		case len(values) == 0:
+			fail(models.AssertionHeaders, headerLine(name, expected), "")
+		case expected != "" && !slices.ContainsFunc(values, func(v string) bool { return strings.TrimSpace(v) == expected }):
+			fail(models.AssertionHeaders, headerLine(name, expected), headerLine(name, strings.Join(values, ", ")))
+		}
+	}
+
+	if a.MaxBodySize > 0 && size > a.MaxBodySize {
+		fail(models.AssertionMaxBodySize, "<= "+strconv.FormatInt(a.MaxBodySize, 10), strconv.FormatInt(size, 10))
+	}
+	if a.MaxLatency > 0 && latency > a.MaxLatency {
+		fail(models.AssertionMaxLatency, "<= "+strconv.FormatInt(a.MaxLatency, 10), strconv.FormatInt(latency, 10))
+	}
+
+	return failures
+}
+
+// evaluateJSON avalia as expressões sobre o corpo interpretado como JSON. O
+// valor encontrado no caminho é registrado em JSON; um caminho inexistente
+// fica sem valor. Um corpo truncado não é interpretado, pois o JSON parcial
+// seria inválido.
+func evaluateJSON(exprs []string, buf *bodyBuffer) []models.AssertionFailure {
+	var failures []models.AssertionFailure
+	fail := func(expected, actual string) {
+		failures = append(failures, models.AssertionFailure{Assertion: models.AssertionJSON, Expected: expected, Actual: actual})
+	}
+
+	var doc any
+	var decodeErr string
+	switch {
+	case buf.truncated:
+		decodeErr = fmt.Sprintf("body exceeds %d bytes; JSON assertions not evaluated", buf.limit)
+	case json.Unmarshal(buf.data, &doc) != nil:
+		decodeErr = "body is not valid JSON"
+	}
+
+	for _, raw := range exprs {
+		expr, err := jsonpath.Parse(raw)
+		switch {
+		case err != nil:
+			fail(raw, err.Error())
+		case decodeErr != "":
+			fail(raw, decodeErr)
+		default:
+			ok, actual, found := expr.Evaluate(doc)
+			if ok {
+				continue
+			}
+			if !found {
+				fail(raw, "")
+				continue
+			}
+			value, _ := json.Marshal(actual)
+			fail(raw, excerpt(value))
+		}
+	}
+	return failures
+}
+
+// assertionError resume as falhas em um erro que envolve ErrAssertionFailed,
+// ou retorna nil quando não há falhas
+func assertionError(failures []models.AssertionFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(failures))
+	for i, f := range failures {
+		actual := f.Actual
+		if actual == "" {
+			actual = "none"
+		}
+		messages[i] = fmt.Sprintf("%s: expected %s, got %s", f.Assertion, f.Expected, actual)
+	}
+	return fmt.Errorf("%w: %s", ErrAssertionFailed, strings.Join(messages, "; "))
+}
+
+func headerLine(name, value string) string {
+	if value == "" {
+		return http.CanonicalHeaderKey(name)
+	}
+	return http.CanonicalHeaderKey(name) + ": " + value
+}
+
+// excerpt retorna o início de data como texto, limitado a maxExcerpt bytes
+func excerpt(data []byte) string {
+	if len(data) <= maxExcerpt {
+		return strings.ToValidUTF8(string(data), "")
+	}
+
+	cut := maxExcerpt
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return strings.ToValidUTF8(string(data[:cut]), "") + "..."
+}
@@ -0,0 +1,405 @@
+package checker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// TestEvaluateAssertions testa cada tipo de asserção isoladamente (white-box)
+func TestEvaluateAssertions(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type":  {"text/html; charset=utf-8"},
+			"Cache-Control": {"no-cache", "no-store"},
+		},
+	}
+	body := []byte("<html><title>Domain parked</title>Buy this domain today</html>")
+
+	tests := []struct {
+		name       string
+		assertions *models.Assertions
+		expected   []models.AssertionFailure
+	}{
+		{"Nil", nil, nil},
+		{"Empty", &models.Assertions{}, nil},
+		{
+			"Status Codes Pass",
+			&models.Assertions{StatusCodes: []int{200, 204}},
+			nil,
+		},
+		{
+			"Status Codes Fail",
+			&models.Assertions{StatusCodes: []int{204}},
+			[]models.AssertionFailure{{Assertion: models.AssertionStatusCodes, Expected: "[204]", Actual: "200"}},
+		},
+		{
+			"Body Contains",
+			&models.Assertions{BodyContains: []string{"parked", "Welcome"}},
+			[]models.AssertionFailure{{Assertion: models.AssertionBodyContains, Expected: "Welcome", Actual: string(body)}},
+		},
+		{
+			"Body Not Contains",
+			&models.Assertions{BodyNotContains: []string{"Buy this domain", "Error"}},
+			[]models.AssertionFailure{{Assertion: models.AssertionBodyNotContains, Expected: "Buy this domain", Actual: "Buy this domain today</html>"}},
+		},
+		{
+			"Body Matches",
+			&models.Assertions{BodyMatches: []string{`<title>[^<]+</title>`, `(?i)dashboard`}},
+			[]models.AssertionFailure{{Assertion: models.AssertionBodyMatches, Expected: `(?i)dashboard`, Actual: string(body)}},
+		},
+		{
+			"Body Not Matches",
+			&models.Assertions{BodyNotMatches: []string{`(?i)domain\s+parked`, `5\d\d`}},
+			[]models.AssertionFailure{{Assertion: models.AssertionBodyNotMatches, Expected: `(?i)domain\s+parked`, Actual: "Domain parked"}},
+		},
+		{
+			"Headers",
+			&models.Assertions{Headers: map[string]string{
+				"content-type":    "text/html; charset=utf-8",
+				"Cache-Control":   "no-store",
+				"X-Frame-Options": "",
+			}},
+			[]models.AssertionFailure{{Assertion: models.AssertionHeaders, Expected: "X-Frame-Options"}},
+		},
+		{
+			"Header Value",
+			&models.Assertions{Headers: map[string]string{"Content-Type": "application/json"}},
+			[]models.AssertionFailure{{
+				Assertion: models.AssertionHeaders,
+				Expected:  "Content-Type: application/json",
+				Actual:    "Content-Type: text/html; charset=utf-8",
+			}},
+		},
+		{
+			"Max Body Size",
+			&models.Assertions{MaxBodySize: 10},
+			[]models.AssertionFailure{{Assertion: models.AssertionMaxBodySize, Expected: "<= 10", Actual: "2048"}},
+		},
+		{
+			"Max Latency",
+			&models.Assertions{MaxLatency: 500},
+			[]models.AssertionFailure{{Assertion: models.AssertionMaxLatency, Expected: "<= 500", Actual: "750"}},
+		},
+		{
+			"Within Limits",
+			&models.Assertions{MaxBodySize: 4096, MaxLatency: 1000},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateAssertions(tt.assertions, resp, &bodyBuffer{data: body}, 2048, 750)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected failures %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	// Cabeçalhos ausentes são relatados em ordem alfabética
+	failures := evaluateAssertions(&models.Assertions{Headers: map[string]string{"Server": "", "Via": ""}}, resp, &bodyBuffer{data: body}, 0, 0)
+	if len(failures) != 2 || failures[0].Expected != "Server" || failures[1].Expected != "Via" {
+		t.Errorf("Expected missing headers in order, got %+v", failures)
+	}
+
+	// Com o corpo truncado, só o que o trecho guardado decide passa
+	truncated := &bodyBuffer{limit: 20}
+	_, _ = truncated.Write(body)
+	failures = evaluateAssertions(&models.Assertions{
+		BodyContains:    []string{"<title>", "today"},
+		BodyNotContains: []string{"<html>", "Buy this domain"},
+		BodyMatches:     []string{`^<html>`, `today`},
+		BodyNotMatches:  []string{`(?i)buy`},
+	}, resp, truncated, int64(len(body)), 0)
+	unseen := "body exceeds 20 bytes; the rest was not evaluated"
+	expected := []models.AssertionFailure{
+		{Assertion: models.AssertionBodyContains, Expected: "today", Actual: unseen},
+		{Assertion: models.AssertionBodyNotContains, Expected: "<html>", Actual: "<html><title>Domain "},
+		{Assertion: models.AssertionBodyNotContains, Expected: "Buy this domain", Actual: unseen},
+		{Assertion: models.AssertionBodyMatches, Expected: "today", Actual: unseen},
+		{Assertion: models.AssertionBodyNotMatches, Expected: `(?i)buy`, Actual: unseen},
+	}
+	if !reflect.DeepEqual(failures, expected) {
+		t.Errorf("Expected failures %+v, got %+v", expected, failures)
+	}
+}
+
+// TestEvaluateJSON testa as asserções JSON e o valor registrado em cada falha (white-box)
+func TestEvaluateJSON(t *testing.T) {
+	body := []byte(`{"status":"degraded","db":{"latency_ms":350,"primary":true},"checks":[{"name":"cache","ok":false}]}`)
+
+	failures := evaluateJSON([]string{
+		`$.status == "ok"`,
+		`$.db.latency_ms < 200`,
+		`$.db.primary == true`,
+		`$.checks[0].ok == true`,
+		`$.checks[0]`,
+		`$.queue.depth < 10`,
+		`$.status = "ok"`,
+	}, &bodyBuffer{data: body})
+
+	expected := []models.AssertionFailure{
+		{Assertion: models.AssertionJSON, Expected: `$.status == "ok"`, Actual: `"degraded"`},
+		{Assertion: models.AssertionJSON, Expected: `$.db.latency_ms < 200`, Actual: "350"},
+		{Assertion: models.AssertionJSON, Expected: `$.checks[0].ok == true`, Actual: "false"},
+		{Assertion: models.AssertionJSON, Expected: `$.queue.depth < 10`},
+		{Assertion: models.AssertionJSON, Expected: `$.status = "ok"`, Actual: `invalid expression "$.status = \"ok\"": unexpected "= \"ok\""`},
+	}
+	if !reflect.DeepEqual(failures, expected) {
+		t.Errorf("Expected failures %+v, got %+v", expected, failures)
+	}
+
+	// Corpo que não é JSON falha todas as expressões
+	failures = evaluateJSON([]string{`$.status == "ok"`, `$.db`}, &bodyBuffer{data: []byte("<html>maintenance</html>")})
+	if len(failures) != 2 || failures[0].Actual != "body is not valid JSON" {
+		t.Errorf("Expected invalid body failures, got %+v", failures)
+	}
+
+	// Um corpo maior que o limite não é interpretado
+	truncated := &bodyBuffer{limit: 16}
+	_, _ = truncated.Write(body)
+	failures = evaluateJSON([]string{`$.status == "ok"`}, truncated)
+	if len(failures) != 1 || failures[0].Actual != "body exceeds 16 bytes; JSON assertions not evaluated" {
+		t.Errorf("Expected truncated body failure, got %+v", failures)
+	}
+}
+
+func TestBodyBuffer(t *testing.T) {
+	buf := &bodyBuffer{limit: 8}
+	for _, chunk := range []string{"abcd", "efgh"} {
+		if n, _ := buf.Write([]byte(chunk)); n != len(chunk) {
+			t.Fatalf("Expected Write to accept %d bytes, got %d", len(chunk), n)
+		}
+	}
+	if string(buf.data) != "abcdefgh" || buf.truncated {
+		t.Errorf("Expected body within the limit to be kept whole, got %q (truncated %v)", buf.data, buf.truncated)
+	}
+
+	_, _ = buf.Write([]byte("i"))
+	if string(buf.data) != "abcdefgh" || !buf.truncated {
+		t.Errorf("Expected bytes past the limit to be dropped, got %q (truncated %v)", buf.data, buf.truncated)
+	}
+}
+
+func TestAssertionError(t *testing.T) {
+	if err := assertionError(nil); err != nil {
+		t.Errorf("Expected nil error without failures, got %v", err)
+	}
+
+	err := assertionError([]models.AssertionFailure{
+		{Assertion: models.AssertionStatusCodes, Expected: "[200]", Actual: "503"},
+		{Assertion: models.AssertionHeaders, Expected: "X-Request-Id"},
+	})
+	if !errors.Is(err, ErrAssertionFailed) {
+		t.Fatalf("Expected ErrAssertionFailed, got %v", err)
+	}
+	expected := "assertion failed: status_codes: expected [200], got 503; headers: expected X-Request-Id, got none"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := excerpt([]byte("short")); got != "short" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+
+	// O corte não divide caracteres multibyte
+	long := strings.Repeat("a", maxExcerpt-1) + "ção"
+	got := excerpt([]byte(long))
+	if got != strings.Repeat("a", maxExcerpt-1)+"..." {
+		t.Errorf("Unexpected excerpt %q", got)
+	}
+}
+
+// TestCheckDomainAssertions testa as asserções de ponta a ponta (white-box)
+func TestCheckDomainAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/parked", http.StatusFound)
+		case "/parked":
+			_, _ = w.Write([]byte("This domain is parked. " + strings.Repeat("x", 100)))
+		default:
+			w.Header().Set("X-Service", "api")
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+	base := "http://assert.test:" + serverPort(t, server)
+
+	t.Run("Pass", func(t *testing.T) {
+		result, err := localChecker(nil).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: base + "/health",
+			Assertions: &models.Assertions{
+				StatusCodes:  []int{200},
+				BodyContains: []string{`"status":"ok"`},
+				Headers:      map[string]string{"X-Service": "api"},
+				MaxLatency:   5000,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Error != "" || len(result.AssertionFailures) != 0 {
+			t.Errorf("Expected assertions to pass, got %q %+v", result.Error, result.AssertionFailures)
+		}
+		if result.Status() != models.StatusUp {
+			t.Errorf("Expected status up, got %s", result.Status())
+		}
+	})
+
+	t.Run("Expected Status Codes", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/private" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		defer server.Close()
+		base := "http://status.test:" + serverPort(t, server)
+
+		// Um 401 esperado mantém o domínio no ar
+		result, _ := localChecker(nil).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        base + "/private",
+			Assertions: &models.Assertions{StatusCodes: []int{401, 403}},
+		})
+		if result.Error != "" || !result.ExpectedStatus || result.Status() != models.StatusUp {
+			t.Errorf("Expected 401 to keep the domain up, got %q (status %s)", result.Error, result.Status())
+		}
+
+		// Um 200 fora da lista derruba o domínio
+		result, _ = localChecker(nil).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        base + "/",
+			Assertions: &models.Assertions{StatusCodes: []int{204}},
+		})
+		if result.ExpectedStatus || result.ErrorKind != models.ErrorKindAssertionFailed || result.Status() != models.StatusDown {
+			t.Errorf("Expected 200 to fail when only 204 is allowed, got %q (status %s)", result.Error, result.Status())
+		}
+
+		// Sem a asserção, 401 continua indicando domínio fora do ar
+		result, _ = localChecker(nil).CheckDomain(&models.Domain{ID: uuid.New(), URL: base + "/private"})
+		if result.Status() != models.StatusDown {
+			t.Errorf("Expected 401 without assertions to be down, got %s", result.Status())
+		}
+	})
+
+	t.Run("Parked Page", func(t *testing.T) {
+		result, err := localChecker(nil).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: base + "/old",
+			Assertions: &models.Assertions{
+				BodyNotContains: []string{"parked"},
+				MaxBodySize:     64,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// As asserções valem para a resposta final, depois do redirecionamento
+		if result.StatusCode != http.StatusOK || result.RedirectCount != 1 {
+			t.Errorf("Expected redirect to be followed, got status %d after %d redirects", result.StatusCode, result.RedirectCount)
+		}
+		if result.ErrorKind != models.ErrorKindAssertionFailed {
+			t.Errorf("Expected ErrorKind %q, got %q", models.ErrorKindAssertionFailed, result.ErrorKind)
+		}
+		if len(result.AssertionFailures) != 2 ||
+			result.AssertionFailures[0].Assertion != models.AssertionBodyNotContains ||
+			result.AssertionFailures[1].Assertion != models.AssertionMaxBodySize {
+			t.Errorf("Unexpected failures %+v", result.AssertionFailures)
+		}
+		if !strings.HasPrefix(result.Error, "assertion failed: body_not_contains") {
+			t.Errorf("Unexpected error message %q", result.Error)
+		}
+		if result.Status() != models.StatusDown {
+			t.Errorf("Expected status down, got %s", result.Status())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		result, err := localChecker(nil).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: base + "/health",
+			Assertions: &models.Assertions{
+				JSON: []string{`$.status == "ok"`, `$.db.latency_ms < 200`},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []models.AssertionFailure{{Assertion: models.AssertionJSON, Expected: `$.db.latency_ms < 200`}}
+		if !reflect.DeepEqual(result.AssertionFailures, expected) {
+			t.Errorf("Expected failures %+v, got %+v", expected, result.AssertionFailures)
+		}
+		if result.Error != "assertion failed: json: expected $.db.latency_ms < 200, got none" {
+			t.Errorf("Unexpected error message %q", result.Error)
+		}
+	})
+
+	t.Run("Asserted Body Limit", func(t *testing.T) {
+		// Texto proibido além do limite não passa despercebido
+		result, _ := localChecker(&Config{MaxAssertedBody: 10}).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        base + "/parked",
+			Assertions: &models.Assertions{BodyNotContains: []string{"parked"}},
+		})
+		expected := []models.AssertionFailure{{
+			Assertion: models.AssertionBodyNotContains,
+			Expected:  "parked",
+			Actual:    "body exceeds 10 bytes; the rest was not evaluated",
+		}}
+		if !reflect.DeepEqual(result.AssertionFailures, expected) {
+			t.Errorf("Expected truncation to be reported, got %+v", result.AssertionFailures)
+		}
+		if result.ContentLength != 123 {
+			t.Errorf("Expected the whole body to be read, got %d bytes", result.ContentLength)
+		}
+
+		// O que já foi encontrado no início do corpo dispensa o restante
+		result, _ = localChecker(&Config{MaxAssertedBody: 10}).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        base + "/parked",
+			Assertions: &models.Assertions{BodyContains: []string{"This"}, BodyMatches: []string{`^This`}},
+		})
+		if result.Error != "" {
+			t.Errorf("Expected matches within the limit to pass, got %q", result.Error)
+		}
+
+		// JSON cortado no limite não é avaliado
+		result, _ = localChecker(&Config{MaxAssertedBody: 10}).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        base + "/health",
+			Assertions: &models.Assertions{JSON: []string{`$.status == "ok"`}},
+		})
+		if len(result.AssertionFailures) != 1 || result.AssertionFailures[0].Actual != "body exceeds 10 bytes; JSON assertions not evaluated" {
+			t.Errorf("Expected truncated JSON to be reported, got %+v", result.AssertionFailures)
+		}
+	})
+
+	t.Run("Transport Errors Take Precedence", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		port := serverPort(t, closed)
+		closed.Close()
+
+		result, _ := localChecker(nil).CheckDomain(&models.Domain{
+			ID:         uuid.New(),
+			URL:        "http://down.test:" + port,
+			Assertions: &models.Assertions{StatusCodes: []int{200}},
+		})
+		if result.ErrorKind == models.ErrorKindAssertionFailed || len(result.AssertionFailures) != 0 {
+			t.Errorf("Expected connection failure without assertions, got %q %+v", result.ErrorKind, result.AssertionFailures)
+		}
+	})
+}
@@ -1,6 +1,17 @@
 package checker
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,37 +19,280 @@ import (
 	"github.com/luizhreis/domain-watcher/internal/models"
 )
 
+const (
+	// defaultScheme é usado quando a URL do domínio não informa esquema
+	defaultScheme = "https"
+	// userAgent identifica as requisições feitas pelo checker
+	userAgent = "domain-watcher"
+)
+
 type checker struct {
 	DNSResolver dns.DNS
+	config      *Config
 }
 
 func NewChecker(dnsResolver dns.DNS) Checker {
+	return NewCheckerWithConfig(dnsResolver, nil)
+}
+
+// NewCheckerWithConfig cria um checker com configuração personalizada.
+// Campos não informados em config usam os valores de DefaultConfig.
+func NewCheckerWithConfig(dnsResolver dns.DNS, config *Config) Checker {
 	return &checker{
 		DNSResolver: dnsResolver,
+		config:      config.withDefaults(),
 	}
 }
 
 var _ Checker = (*checker)(nil)
 
 func (c *checker) CheckDomain(domain *models.Domain) (*models.CheckResult, error) {
+	return c.CheckDomainContext(context.Background(), domain)
+}
+
+// CheckDomainContext verifica o domínio dentro do prazo de Domain.Timeout (ou
+// Config.DefaultTimeout), que cobre a resolução DNS, a conexão, o handshake
+// TLS e a leitura da resposta, inclusive dos redirecionamentos.
+func (c *checker) CheckDomainContext(ctx context.Context, domain *models.Domain) (*models.CheckResult, error) {
+	timeout := c.timeout(domain)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := c.check(checkCtx, domain, timeout)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return result, err
+}
+
+// check executa a verificação sob ctx, que carrega o prazo de timeout. Falhas
+// do domínio são registradas no resultado; o erro retornado indica apenas que
+// não foi possível verificar.
+func (c *checker) check(ctx context.Context, domain *models.Domain, timeout time.Duration) (*models.CheckResult, error) {
 	timestamp := time.Now()
 
-	resolvedIP, err := c.DNSResolver.Resolve(domain.URL)
+	target, err := parseTarget(domain.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.CheckResult{
-		ID:            uuid.New(),
-		DomainID:      domain.ID,
-		StatusCode:    200,
-		ResponseTime:  100,
-		Error:         "",
-		RedirectURL:   "",
-		RedirectCount: 0,
-		CheckedAt:     timestamp,
-		ContentLength: 1024,
-		Server:        "nginx",
-		ResolvedIP:    resolvedIP,
-	}, nil
+	result := &models.CheckResult{
+		ID:        uuid.New(),
+		DomainID:  domain.ID,
+		CheckedAt: timestamp,
+	}
+
+	tracker := newPhaseTracker()
+	defer func() {
+		result.Timings = tracker.timings()
+	}()
+
+	// Falhas de resolução geram um resultado com falha, para que a
+	// indisponibilidade seja registrada
+	tracker.startDNS()
+	resolvedIPs, err := c.DNSResolver.ResolveAllContext(ctx, target.Hostname())
+	tracker.doneDNS()
+	if err == nil && len(resolvedIPs) == 0 {
+		err = &dns.LookupError{Domain: target.Hostname(), Kind: dns.ErrNoAnswer}
+	}
+	if err != nil {
+		recordFailure(ctx, result, err, classifyDNSError(err), tracker.phase(), timeout)
+		return result, nil
+	}
+	resolvedIP := resolvedIPs[0]
+
+	result.ResolvedIP = resolvedIP
+	result.ResolvedIPs = resolvedIPs
+	result.UnexpectedIP = !domain.MatchesExpectedIPs(resolvedIPs)
+
+	ctx = httptrace.WithClientTrace(ctx, tracker.trace())
+	if err := c.probe(ctx, target, resolvedIP, domain.Assertions, result, tracker); err != nil {
+		recordFailure(ctx, result, err, classifyError(err), tracker.phase(), timeout)
+	}
+
+	return result, nil
+}
+
+// recordFailure registra err no resultado. Quando o prazo da verificação
+// expirou, a falha é registrada como timeout da etapa em andamento.
+func recordFailure(ctx context.Context, result *models.CheckResult, err error, kind models.ErrorKind, p phase, timeout time.Duration) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Error = fmt.Sprintf("timeout after %s during %s", timeout, p)
+		result.ErrorKind = p.timeoutKind()
+		return
+	}
+
+	result.Error = err.Error()
+	result.ErrorKind = kind
+}
+
+// probe executa a requisição HTTP, seguindo redirecionamentos, preenche o
+// resultado com a resposta real e avalia as asserções na resposta final. O
+// erro retornado descreve a falha da verificação e deve ser registrado no
+// resultado.
+func (c *checker) probe(ctx context.Context, target *url.URL, resolvedIP string, assertions *models.Assertions, result *models.CheckResult, tracker *phaseTracker) error {
+	inspector := &tlsInspector{roots: c.config.RootCAs}
+	client := c.newHTTPClient(target.Hostname(), resolvedIP, inspector.clientConfig())
+	defer client.CloseIdleConnections()
+
+	start := time.Now()
+	defer func() {
+		result.ResponseTime = time.Since(start).Milliseconds()
+		result.TLS = inspector.info
+	}()
+
+	chain := newRedirectTracker(target)
+	current := target
+
+	for {
+		hopStart := time.Now()
+		resp, err := c.fetch(ctx, client, current)
+		if err != nil {
+			return err
+		}
+
+		result.StatusCode = resp.StatusCode
+		result.Server = resp.Header.Get("Server")
+
+		// O corpo só é guardado quando alguma asserção depende dele
+		body := &bodyBuffer{}
+		if assertions.HasBodyChecks() {
+			body.limit = c.config.MaxAssertedBody
+		}
+		n, err := io.Copy(body, resp.Body)
+		tracker.bodyRead()
+		resp.Body.Close()
+		result.ContentLength = n
+		if err != nil {
+			return err
+		}
+
+		next, isRedirect := redirectTarget(current, resp)
+		chain.record(current, resp, time.Since(hopStart), next)
+
+		if !isRedirect {
+			if chain.count() > 0 {
+				result.RedirectURL = current.String()
+				result.RedirectChain = chain.result()
+			}
+			result.ExpectedStatus = assertions != nil && slices.Contains(assertions.StatusCodes, resp.StatusCode)
+			result.AssertionFailures = evaluateAssertions(assertions, resp, body, n, time.Since(start).Milliseconds())
+			return assertionError(result.AssertionFailures)
+		}
+
+		result.RedirectCount = chain.count()
+		result.RedirectURL = next.String()
+
+		var redirectErr error
+		switch {
+		case chain.visited(next):
+			chain.markLoop()
+			redirectErr = ErrRedirectLoop
+		case chain.count() > c.config.MaxRedirects:
+			redirectErr = ErrTooManyRedirects
+		}
+
+		result.RedirectChain = chain.result()
+		if redirectErr != nil {
+			return redirectErr
+		}
+
+		current = next
+	}
+}
+
+// fetch executa uma única requisição GET sem seguir redirecionamentos.
+func (c *checker) fetch(ctx context.Context, client *http.Client, target *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	return client.Do(req)
+}
+
+// newHTTPClient cria um cliente dedicado à verificação. As conexões para o host
+// verificado usam o IP já resolvido; os demais hosts passam pelo DNSResolver.
+func (c *checker) newHTTPClient(host, resolvedIP string, tlsConfig *tls.Config) *http.Client {
+	dialer := &net.Dialer{}
+
+	transport := &http.Transport{
+		Proxy:             nil,
+		DisableKeepAlives: true,
+		TLSClientConfig:   tlsConfig,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			h, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			ip := h
+			switch {
+			case strings.EqualFold(h, host):
+				ip = resolvedIP
+			case net.ParseIP(h) == nil:
+				if ip, err = c.resolve(ctx, h); err != nil {
+					return nil, err
+				}
+			}
+
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		},
+	}
+
+	return &http.Client{
+		Transport: transport,
+		// Os redirecionamentos são seguidos manualmente em probe
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// resolve resolve host pelo DNSResolver, informando a consulta ao httptrace
+// de ctx, como faria o resolver padrão do transporte
+func (c *checker) resolve(ctx context.Context, host string) (string, error) {
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+
+	ip, err := c.DNSResolver.ResolveContext(ctx, host)
+
+	if trace != nil && trace.DNSDone != nil {
+		info := httptrace.DNSDoneInfo{Err: err}
+		if err == nil {
+			info.Addrs = []net.IPAddr{{IP: net.ParseIP(ip)}}
+		}
+		trace.DNSDone(info)
+	}
+	return ip, err
+}
+
+// parseTarget interpreta a URL do domínio, assumindo HTTPS quando não há esquema.
+func parseTarget(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = defaultScheme + "://" + rawURL
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Hostname() == "" {
+		return nil, ErrInvalidURL
+	}
+
+	return target, nil
+}
+
+// timeout converte Domain.Timeout (em segundos) para time.Duration, usando
+// Config.DefaultTimeout quando o domínio não define um prazo.
+func (c *checker) timeout(domain *models.Domain) time.Duration {
+	if domain.Timeout <= 0 {
+		return c.config.DefaultTimeout
+	}
+	return time.Duration(domain.Timeout) * time.Second
 }
@@ -1,11 +1,18 @@
 package checker
 
 import (
+	"context"
 	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/dns"
 	"github.com/luizhreis/domain-watcher/internal/models"
 )
 
@@ -21,6 +28,54 @@ func (m *MockDNS) Resolve(domain string) (string, error) {
 	return "192.168.1.1", nil
 }
 
+func (m *MockDNS) ResolveAll(domain string) ([]string, error) {
+	ip, err := m.Resolve(domain)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(ip, ","), nil
+}
+
+func (m *MockDNS) Lookup(domain string) (*dns.Records, error) {
+	ip, err := m.Resolve(domain)
+	if err != nil {
+		return nil, err
+	}
+	return &dns.Records{Name: domain, A: []dns.IPRecord{{IP: ip}}}, nil
+}
+
+func (m *MockDNS) LookupType(domain string, _ dns.RecordType) (*dns.Records, error) {
+	return m.Lookup(domain)
+}
+
+func (m *MockDNS) ResolveContext(ctx context.Context, domain string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return m.Resolve(domain)
+}
+
+func (m *MockDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ResolveAll(domain)
+}
+
+func (m *MockDNS) LookupContext(ctx context.Context, domain string) (*dns.Records, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Lookup(domain)
+}
+
+func (m *MockDNS) LookupTypeContext(ctx context.Context, domain string, recordType dns.RecordType) (*dns.Records, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LookupType(domain, recordType)
+}
+
 // TestNewChecker testa a criação do checker (white-box)
 func TestNewChecker(t *testing.T) {
 	mockDNS := &MockDNS{}
@@ -44,10 +99,20 @@ func TestNewChecker(t *testing.T) {
 // TestCheckerCheckDomain testa CheckDomain com acesso interno (white-box)
 func TestCheckerCheckDomain(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
+		body := "hello from domain-watcher"
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Server", "unit-test-server")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(body))
+		}))
+		defer server.Close()
+
 		mockDNS := &MockDNS{}
-		expectedIP := "192.168.1.200"
+		expectedIP := "127.0.0.1"
+		var resolvedHost string
 
 		mockDNS.resolveFunc = func(domain string) (string, error) {
+			resolvedHost = domain
 			return expectedIP, nil
 		}
 
@@ -56,7 +121,7 @@ func TestCheckerCheckDomain(t *testing.T) {
 		domain := &models.Domain{
 			ID:   testDomainID,
 			Name: "Unit Test",
-			URL:  "unit.test",
+			URL:  "http://unit.test:" + serverPort(t, server) + "/health",
 		}
 
 		beforeTime := time.Now()
@@ -81,12 +146,16 @@ func TestCheckerCheckDomain(t *testing.T) {
 			t.Errorf("Expected DomainID %d, got %d", domain.ID, result.DomainID)
 		}
 
-		if result.StatusCode != 200 {
-			t.Errorf("Expected StatusCode 200, got %d", result.StatusCode)
+		if resolvedHost != "unit.test" {
+			t.Errorf("Expected DNS lookup for 'unit.test', got '%s'", resolvedHost)
 		}
 
-		if result.ResponseTime != 100 {
-			t.Errorf("Expected ResponseTime 100, got %d", result.ResponseTime)
+		if result.StatusCode != http.StatusAccepted {
+			t.Errorf("Expected StatusCode %d, got %d", http.StatusAccepted, result.StatusCode)
+		}
+
+		if result.ResponseTime < 0 || result.ResponseTime > afterTime.Sub(beforeTime).Milliseconds() {
+			t.Errorf("ResponseTime %d out of measured bounds", result.ResponseTime)
 		}
 
 		if result.Error != "" {
@@ -101,12 +170,12 @@ func TestCheckerCheckDomain(t *testing.T) {
 			t.Errorf("Expected RedirectCount 0, got %d", result.RedirectCount)
 		}
 
-		if result.ContentLength != 1024 {
-			t.Errorf("Expected ContentLength 1024, got %d", result.ContentLength)
+		if result.ContentLength != int64(len(body)) {
+			t.Errorf("Expected ContentLength %d, got %d", len(body), result.ContentLength)
 		}
 
-		if result.Server != "nginx" {
-			t.Errorf("Expected Server 'nginx', got %s", result.Server)
+		if result.Server != "unit-test-server" {
+			t.Errorf("Expected Server 'unit-test-server', got %s", result.Server)
 		}
 
 		if result.ResolvedIP != expectedIP {
@@ -124,6 +193,165 @@ func TestCheckerCheckDomain(t *testing.T) {
 		}
 	})
 
+	t.Run("Connection Error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		port := serverPort(t, server)
+		server.Close() // porta fechada: conexão deve ser recusada
+
+		checkerInstance := NewChecker(&MockDNS{
+			resolveFunc: func(domain string) (string, error) { return "127.0.0.1", nil },
+		})
+		domain := &models.Domain{
+			ID:  uuid.New(),
+			URL: "http://down.test:" + port,
+		}
+
+		result, err := checkerInstance.CheckDomain(domain)
+
+		// Falhas HTTP não são erros do checker: ficam registradas no resultado
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Error == "" {
+			t.Error("Expected result.Error to describe the connection failure")
+		}
+
+		if result.StatusCode != 0 {
+			t.Errorf("Expected StatusCode 0, got %d", result.StatusCode)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		checkerInstance := NewChecker(&MockDNS{
+			resolveFunc: func(domain string) (string, error) { return "127.0.0.1", nil },
+		})
+		domain := &models.Domain{
+			ID:      uuid.New(),
+			URL:     "http://slow.test:" + serverPort(t, server),
+			Timeout: 1,
+		}
+
+		start := time.Now()
+		result, err := checkerInstance.CheckDomain(domain)
+		elapsed := time.Since(start)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Error == "" {
+			t.Error("Expected timeout to be recorded in result.Error")
+		}
+
+		if result.ErrorKind != models.ErrorKindResponseTimeout {
+			t.Errorf("Expected ErrorKind %q, got %q", models.ErrorKindResponseTimeout, result.ErrorKind)
+		}
+
+		if elapsed > 3*time.Second {
+			t.Errorf("Expected check to honor 1s timeout, took %v", elapsed)
+		}
+	})
+
+	t.Run("Context Canceled", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		checkerInstance := NewChecker(&MockDNS{
+			resolveFunc: func(domain string) (string, error) { return "127.0.0.1", nil },
+		})
+		domain := &models.Domain{
+			ID:  uuid.New(),
+			URL: "http://slow.test:" + serverPort(t, server),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		result, err := checkerInstance.CheckDomainContext(ctx, domain)
+		elapsed := time.Since(start)
+
+		// A interrupção não é uma falha do domínio e não gera resultado
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected no result for a canceled check, got %+v", result)
+		}
+		if elapsed > time.Second {
+			t.Errorf("Expected check to stop on cancel, took %v", elapsed)
+		}
+
+		// Contexto já cancelado interrompe antes da resolução DNS
+		if _, err := checkerInstance.CheckDomainContext(ctx, domain); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Expected IPs", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		checkerInstance := NewChecker(&MockDNS{
+			resolveFunc: func(domain string) (string, error) { return "127.0.0.1,::1", nil },
+		})
+
+		tests := []struct {
+			expectedIP string
+			unexpected bool
+		}{
+			{"", false},
+			{"127.0.0.1, ::1", false},
+			{"127.0.0.1", true},
+		}
+
+		for _, tt := range tests {
+			result, err := checkerInstance.CheckDomain(&models.Domain{
+				ID:  uuid.New(),
+				URL: "http://expected.test:" + serverPort(t, server),
+				IP:  tt.expectedIP,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(result.ResolvedIPs) != 2 || result.ResolvedIP != "127.0.0.1" {
+				t.Errorf("Expected all resolved IPs to be recorded, got %v", result.ResolvedIPs)
+			}
+			if result.UnexpectedIP != tt.unexpected {
+				t.Errorf("Domain.IP %q: expected UnexpectedIP %v, got %v", tt.expectedIP, tt.unexpected, result.UnexpectedIP)
+			}
+		}
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		mockDNS := &MockDNS{}
+		checkerInstance := NewChecker(mockDNS)
+
+		_, err := checkerInstance.CheckDomain(&models.Domain{ID: uuid.New(), URL: "http://"})
+		if !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("Expected ErrInvalidURL, got %v", err)
+		}
+	})
+
 	t.Run("DNS Error", func(t *testing.T) {
 		mockDNS := &MockDNS{}
 		expectedError := errors.New("DNS lookup failed")
@@ -142,17 +370,58 @@ func TestCheckerCheckDomain(t *testing.T) {
 
 		result, err := checkerInstance.CheckDomain(domain)
 
-		// White-box: testa propagação de erro específica
-		if err == nil {
-			t.Error("Expected error, got nil")
+		// White-box: falha de DNS gera resultado com falha, sem erro
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		if err != expectedError {
-			t.Errorf("Expected exact error reference, got different error")
+		if result == nil {
+			t.Fatal("Expected failed result, got nil")
 		}
 
-		if result != nil {
-			t.Error("Expected result to be nil when error occurs")
+		if result.DomainID != testDomainID {
+			t.Errorf("Expected DomainID %v, got %v", testDomainID, result.DomainID)
+		}
+
+		if result.Error != expectedError.Error() {
+			t.Errorf("Expected error %q, got %q", expectedError.Error(), result.Error)
+		}
+
+		if result.ErrorKind != models.ErrorKindDNS {
+			t.Errorf("Expected ErrorKind %q, got %q", models.ErrorKindDNS, result.ErrorKind)
+		}
+
+		if result.StatusCode != 0 || result.ResolvedIP != "" {
+			t.Errorf("Expected no HTTP data, got status %d and IP %q", result.StatusCode, result.ResolvedIP)
+		}
+	})
+
+	t.Run("Classified DNS Errors", func(t *testing.T) {
+		tests := []struct {
+			kind     error
+			expected models.ErrorKind
+		}{
+			{dns.ErrNXDomain, models.ErrorKindDNSNXDomain},
+			{dns.ErrServFail, models.ErrorKindDNSServFail},
+			{dns.ErrTimeout, models.ErrorKindDNSTimeout},
+			{dns.ErrRefused, models.ErrorKindDNSRefused},
+			{dns.ErrNoAnswer, models.ErrorKindDNSNoAnswer},
+		}
+
+		for _, tt := range tests {
+			mockDNS := &MockDNS{}
+			mockDNS.resolveFunc = func(domain string) (string, error) {
+				return "", &dns.LookupError{Domain: domain, Kind: tt.kind}
+			}
+
+			result, err := NewChecker(mockDNS).CheckDomain(&models.Domain{ID: uuid.New(), URL: "failed.test"})
+			if err != nil {
+				t.Fatalf("%v: expected no error, got %v", tt.kind, err)
+			}
+
+			if result.ErrorKind != tt.expected {
+				t.Errorf("%v: expected ErrorKind %q, got %q", tt.kind, tt.expected, result.ErrorKind)
+			}
 		}
 	})
 
@@ -173,11 +442,200 @@ func TestCheckerCheckDomain(t *testing.T) {
 	})
 }
 
+// serverPort extrai a porta de um servidor httptest
+func serverPort(tb testing.TB, server *httptest.Server) string {
+	tb.Helper()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		tb.Fatalf("Invalid test server URL: %v", err)
+	}
+
+	_, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		tb.Fatalf("Invalid test server host: %v", err)
+	}
+	return port
+}
+
+// TestParseTarget testa a interpretação da URL do domínio (white-box)
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		expected string
+		wantErr  bool
+	}{
+		{"Bare host defaults to https", "example.com", "https://example.com", false},
+		{"Explicit http", "http://example.com/path", "http://example.com/path", false},
+		{"Host with port", "example.com:8443", "https://example.com:8443", false},
+		{"Missing host", "https://", "", true},
+		{"Empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := parseTarget(tt.rawURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %v", tt.rawURL, target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if target.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, target.String())
+			}
+		})
+	}
+}
+
+// TestDomainTimeout testa a conversão de Domain.Timeout e o padrão
+// configurável (white-box)
+func TestDomainTimeout(t *testing.T) {
+	c := NewChecker(&MockDNS{}).(*checker)
+	if got := c.timeout(&models.Domain{}); got != DefaultTimeout {
+		t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, got)
+	}
+
+	if got := c.timeout(&models.Domain{Timeout: 5}); got != 5*time.Second {
+		t.Errorf("Expected 5s, got %v", got)
+	}
+
+	c = NewCheckerWithConfig(&MockDNS{}, &Config{DefaultTimeout: 2 * time.Second}).(*checker)
+	if got := c.timeout(&models.Domain{}); got != 2*time.Second {
+		t.Errorf("Expected configured default 2s, got %v", got)
+	}
+	if got := c.timeout(&models.Domain{Timeout: 5}); got != 5*time.Second {
+		t.Errorf("Expected domain timeout to override the default, got %v", got)
+	}
+}
+
+// blockingDNS só responde quando ctx termina, como um servidor que não responde
+type blockingDNS struct {
+	MockDNS
+}
+
+func (b *blockingDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	<-ctx.Done()
+	return nil, &dns.LookupError{Domain: domain, Kind: dns.ErrTimeout, Err: ctx.Err()}
+}
+
+// stalledListener aceita conexões TCP e nunca responde, travando o handshake TLS
+func stalledListener(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var conns []net.Conn
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				for _, c := range conns {
+					c.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	t.Cleanup(func() {
+		ln.Close()
+		<-done
+	})
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+// TestCheckDomainTimeout testa que o prazo cobre toda a verificação e é
+// classificado pela etapa em que expirou (white-box)
+func TestCheckDomainTimeout(t *testing.T) {
+	localhost := func(domain string) (string, error) { return "127.0.0.1", nil }
+
+	t.Run("DNS", func(t *testing.T) {
+		c := NewCheckerWithConfig(&blockingDNS{}, &Config{DefaultTimeout: 100 * time.Millisecond})
+
+		start := time.Now()
+		result, err := c.CheckDomain(&models.Domain{ID: uuid.New(), URL: "http://slow-dns.test"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected DNS to be bounded by the timeout, took %v", elapsed)
+		}
+		if result.ErrorKind != models.ErrorKindDNSTimeout || !result.ErrorKind.IsTimeout() {
+			t.Errorf("Expected ErrorKind %q, got %q", models.ErrorKindDNSTimeout, result.ErrorKind)
+		}
+		if result.Error != "timeout after 100ms during dns lookup" {
+			t.Errorf("Unexpected error message %q", result.Error)
+		}
+	})
+
+	t.Run("TLS Handshake", func(t *testing.T) {
+		c := NewCheckerWithConfig(&MockDNS{resolveFunc: localhost}, &Config{DefaultTimeout: 100 * time.Millisecond})
+
+		start := time.Now()
+		result, err := c.CheckDomain(&models.Domain{ID: uuid.New(), URL: "https://stalled.test:" + stalledListener(t)})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected handshake to be bounded by the timeout, took %v", elapsed)
+		}
+		if result.ErrorKind != models.ErrorKindTLSTimeout {
+			t.Errorf("Expected ErrorKind %q, got %q (%s)", models.ErrorKindTLSTimeout, result.ErrorKind, result.Error)
+		}
+	})
+
+	t.Run("Domain Overrides Default", func(t *testing.T) {
+		c := NewCheckerWithConfig(&blockingDNS{}, &Config{DefaultTimeout: time.Hour})
+
+		start := time.Now()
+		result, err := c.CheckDomain(&models.Domain{ID: uuid.New(), URL: "http://slow-dns.test", Timeout: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("Expected the 1s domain timeout, took %v", elapsed)
+		}
+		if !result.ErrorKind.IsTimeout() {
+			t.Errorf("Expected a timeout, got %q", result.ErrorKind)
+		}
+	})
+
+	t.Run("Failures Before Deadline", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		port := serverPort(t, server)
+		server.Close()
+
+		c := NewChecker(&MockDNS{resolveFunc: localhost})
+		result, err := c.CheckDomain(&models.Domain{ID: uuid.New(), URL: "http://down.test:" + port})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.ErrorKind.IsTimeout() {
+			t.Errorf("Expected refused connection not to be a timeout, got %q", result.ErrorKind)
+		}
+	})
+}
+
 // BenchmarkCheckerCheckDomain benchmark unitário
 func BenchmarkCheckerCheckDomain(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
 	mockDNS := &MockDNS{}
 	mockDNS.resolveFunc = func(domain string) (string, error) {
-		return "192.168.1.1", nil
+		return "127.0.0.1", nil
 	}
 
 	checkerInstance := NewChecker(mockDNS)
@@ -185,7 +643,7 @@ func BenchmarkCheckerCheckDomain(b *testing.B) {
 	domain := &models.Domain{
 		ID:   testDomainID,
 		Name: "Benchmark Domain",
-		URL:  "benchmark.test",
+		URL:  "http://benchmark.test:" + serverPort(b, server),
 	}
 
 	b.ResetTimer()
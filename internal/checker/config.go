@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+const (
+	// DefaultMaxRedirects é o limite de saltos usado quando Config.MaxRedirects é zero
+	DefaultMaxRedirects = 10
+	// DefaultTimeout é o prazo usado quando Config.DefaultTimeout é zero
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxAssertedBody é o limite usado quando Config.MaxAssertedBody é zero
+	DefaultMaxAssertedBody = 1 << 20
+)
+
+// Config define o comportamento do checker
+type Config struct {
+	// MaxRedirects limita quantos redirecionamentos são seguidos por verificação
+	MaxRedirects int `json:"max_redirects,omitempty"`
+
+	// DefaultTimeout é o prazo de cada verificação, da resolução DNS à
+	// leitura da resposta, para domínios sem Timeout próprio
+	DefaultTimeout time.Duration `json:"default_timeout,omitempty"`
+
+	// RootCAs define as autoridades confiáveis na validação TLS.
+	// Quando nil, usa as raízes do sistema.
+	RootCAs *x509.CertPool `json:"-"`
+
+	// MaxAssertedBody limita quantos bytes do corpo são guardados para as
+	// asserções de conteúdo; o restante é lido, mas não é avaliado
+	MaxAssertedBody int64 `json:"max_asserted_body,omitempty"`
+}
+
+// DefaultConfig retorna a configuração padrão do checker
+func DefaultConfig() *Config {
+	return &Config{
+		MaxRedirects:    DefaultMaxRedirects,
+		DefaultTimeout:  DefaultTimeout,
+		MaxAssertedBody: DefaultMaxAssertedBody,
+	}
+}
+
+// withDefaults preenche os campos não informados com os valores padrão
+func (c *Config) withDefaults() *Config {
+	cfg := DefaultConfig()
+	if c == nil {
+		return cfg
+	}
+
+	if c.MaxRedirects > 0 {
+		cfg.MaxRedirects = c.MaxRedirects
+	}
+	if c.DefaultTimeout > 0 {
+		cfg.DefaultTimeout = c.DefaultTimeout
+	}
+
+	if c.MaxAssertedBody > 0 {
+		cfg.MaxAssertedBody = c.MaxAssertedBody
+	}
+
+	cfg.RootCAs = c.RootCAs
+
+	return cfg
+}
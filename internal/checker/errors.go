@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"errors"
+
+	"github.com/luizhreis/domain-watcher/internal/dns"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+var (
+	ErrInvalidURL          = errors.New("invalid domain URL")
+	ErrTooManyRedirects    = errors.New("too many redirects")
+	ErrRedirectLoop        = errors.New("redirect loop detected")
+	ErrTLSHostnameMismatch = errors.New("tls hostname mismatch")
+	ErrTLSUntrustedChain   = errors.New("tls untrusted certificate chain")
+	ErrTLSExpired          = errors.New("tls certificate expired")
+	ErrTLSNotYetValid      = errors.New("tls certificate not yet valid")
+	ErrAssertionFailed     = errors.New("assertion failed")
+)
+
+// errorKinds associa os erros do checker à classificação gravada no resultado
+var errorKinds = []struct {
+	err  error
+	kind models.ErrorKind
+}{
+	{ErrTLSHostnameMismatch, models.ErrorKindTLSHostnameMismatch},
+	{ErrTLSUntrustedChain, models.ErrorKindTLSUntrustedChain},
+	{ErrTLSExpired, models.ErrorKindTLSExpired},
+	{ErrTLSNotYetValid, models.ErrorKindTLSNotYetValid},
+	{ErrAssertionFailed, models.ErrorKindAssertionFailed},
+	{dns.ErrNXDomain, models.ErrorKindDNSNXDomain},
+	{dns.ErrServFail, models.ErrorKindDNSServFail},
+	{dns.ErrTimeout, models.ErrorKindDNSTimeout},
+	{dns.ErrRefused, models.ErrorKindDNSRefused},
+	{dns.ErrNoAnswer, models.ErrorKindDNSNoAnswer},
+}
+
+// classifyError retorna a classificação de err, ou "" quando não há uma específica
+func classifyError(err error) models.ErrorKind {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// classifyDNSError classifica uma falha de resolução; erros sem classificação
+// específica recebem ErrorKindDNS
+func classifyDNSError(err error) models.ErrorKind {
+	if kind := classifyError(err); kind != "" {
+		return kind
+	}
+	return models.ErrorKindDNS
+}
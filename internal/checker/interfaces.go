@@ -1,7 +1,15 @@
 package checker
 
-import "github.com/luizhreis/domain-watcher/internal/models"
+import (
+	"context"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
 
 type Checker interface {
 	CheckDomain(domain *models.Domain) (*models.CheckResult, error)
+	// CheckDomainContext interrompe a resolução DNS e a requisição quando ctx
+	// é cancelado ou seu prazo expira. Nesse caso retorna o erro de ctx e
+	// nenhum resultado, pois a interrupção não diz nada sobre o domínio.
+	CheckDomainContext(ctx context.Context, domain *models.Domain) (*models.CheckResult, error)
 }
@@ -0,0 +1,145 @@
+package checker
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// phase é a etapa em andamento da verificação, usada para classificar a
+// expiração do prazo do domínio
+type phase string
+
+const (
+	phaseDNS      phase = "dns lookup"
+	phaseConnect  phase = "connect"
+	phaseTLS      phase = "tls handshake"
+	phaseResponse phase = "response"
+)
+
+// timeoutKind retorna a classificação da expiração do prazo nesta etapa
+func (p phase) timeoutKind() models.ErrorKind {
+	switch p {
+	case phaseConnect:
+		return models.ErrorKindConnectTimeout
+	case phaseTLS:
+		return models.ErrorKindTLSTimeout
+	case phaseResponse:
+		return models.ErrorKindResponseTimeout
+	default:
+		return models.ErrorKindDNSTimeout
+	}
+}
+
+// phaseTracker acompanha a etapa atual e soma a duração de cada uma. Os
+// ganchos do httptrace podem ser chamados de outras goroutines do
+// transporte, por isso o acesso é travado.
+type phaseTracker struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	current phase
+
+	dns, connect, tls, firstByte, transfer phaseTimer
+}
+
+// phaseTimer soma a duração de uma etapa que pode ocorrer várias vezes,
+// uma por salto de redirecionamento
+type phaseTimer struct {
+	total   time.Duration
+	started time.Time
+}
+
+func (pt *phaseTimer) start(now time.Time) { pt.started = now }
+
+func (pt *phaseTimer) stop(now time.Time) {
+	if !pt.started.IsZero() {
+		pt.total += now.Sub(pt.started)
+		pt.started = time.Time{}
+	}
+}
+
+// elapsed inclui a medição em andamento, para que uma etapa interrompida
+// pelo prazo apareça com o tempo já gasto
+func (pt *phaseTimer) elapsed(now time.Time) int64 {
+	total := pt.total
+	if !pt.started.IsZero() {
+		total += now.Sub(pt.started)
+	}
+	return total.Milliseconds()
+}
+
+func newPhaseTracker() *phaseTracker {
+	return &phaseTracker{now: time.Now, current: phaseDNS}
+}
+
+func (t *phaseTracker) phase() phase {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+// enter marca p como etapa atual, encerra a medição de stop e inicia a de
+// start; ambos podem ser nil
+func (t *phaseTracker) enter(p phase, stop, start *phaseTimer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.current = p
+	if stop != nil {
+		stop.stop(now)
+	}
+	if start != nil {
+		start.start(now)
+	}
+}
+
+// stop encerra a medição de timer sem mudar de etapa
+func (t *phaseTracker) stop(timer *phaseTimer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timer.stop(t.now())
+}
+
+// startDNS e doneDNS medem as resoluções feitas fora do transporte HTTP
+func (t *phaseTracker) startDNS() { t.enter(phaseDNS, nil, &t.dns) }
+func (t *phaseTracker) doneDNS()  { t.stop(&t.dns) }
+
+// bodyRead encerra a medição da transferência do corpo da resposta
+func (t *phaseTracker) bodyRead() { t.stop(&t.transfer) }
+
+// trace avança a etapa conforme o progresso de cada requisição e mede as
+// durações. Cada salto de redirecionamento abre uma nova conexão e recomeça
+// em phaseConnect. O tempo até o primeiro byte é medido a partir do envio da
+// requisição e a transferência, a partir do primeiro byte.
+func (t *phaseTracker) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn:              func(string) { t.enter(phaseConnect, nil, nil) },
+		DNSStart:             func(httptrace.DNSStartInfo) { t.startDNS() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.doneDNS() },
+		ConnectStart:         func(string, string) { t.enter(phaseConnect, nil, &t.connect) },
+		ConnectDone:          func(string, string, error) { t.stop(&t.connect) },
+		TLSHandshakeStart:    func() { t.enter(phaseTLS, nil, &t.tls) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.stop(&t.tls) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.enter(phaseResponse, nil, &t.firstByte) },
+		GotFirstResponseByte: func() { t.enter(phaseResponse, &t.firstByte, &t.transfer) },
+	}
+}
+
+// timings retorna as durações acumuladas, em milissegundos
+func (t *phaseTracker) timings() *models.Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	return &models.Timings{
+		DNSLookup:       t.dns.elapsed(now),
+		TCPConnect:      t.connect.elapsed(now),
+		TLSHandshake:    t.tls.elapsed(now),
+		TimeToFirstByte: t.firstByte.elapsed(now),
+		ContentTransfer: t.transfer.elapsed(now),
+	}
+}
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// TestPhaseTracker testa a soma das durações por etapa com um relógio
+// controlado (white-box)
+func TestPhaseTracker(t *testing.T) {
+	clock := time.Unix(0, 0)
+	advance := func(ms int) { clock = clock.Add(time.Duration(ms) * time.Millisecond) }
+
+	tracker := newPhaseTracker()
+	tracker.now = func() time.Time { return clock }
+	trace := tracker.trace()
+
+	tracker.startDNS()
+	advance(5)
+	tracker.doneDNS()
+
+	// Dois saltos: o segundo é HTTPS e tem handshake TLS
+	for _, secure := range []bool{false, true} {
+		trace.GetConn("example.com:443")
+		trace.ConnectStart("tcp", "127.0.0.1:443")
+		advance(10)
+		trace.ConnectDone("tcp", "127.0.0.1:443", nil)
+		if secure {
+			trace.TLSHandshakeStart()
+			if got := tracker.phase(); got != phaseTLS {
+				t.Errorf("Expected phase %q, got %q", phaseTLS, got)
+			}
+			advance(20)
+			trace.TLSHandshakeDone(tls.ConnectionState{}, nil)
+		}
+		trace.WroteRequest(httptrace.WroteRequestInfo{})
+		advance(30)
+		trace.GotFirstResponseByte()
+		advance(4)
+		tracker.bodyRead()
+	}
+
+	expected := models.Timings{DNSLookup: 5, TCPConnect: 20, TLSHandshake: 20, TimeToFirstByte: 60, ContentTransfer: 8}
+	if got := tracker.timings(); *got != expected {
+		t.Errorf("Expected timings %+v, got %+v", expected, *got)
+	}
+
+	// Uma etapa interrompida conta o tempo já gasto
+	trace.WroteRequest(httptrace.WroteRequestInfo{})
+	advance(15)
+	if got := tracker.timings().TimeToFirstByte; got != 75 {
+		t.Errorf("Expected in-progress time to first byte 75, got %d", got)
+	}
+}
+
+// TestCheckDomainTimings testa que a verificação registra a duração de cada
+// etapa (white-box)
+func TestCheckDomainTimings(t *testing.T) {
+	const delay = 30 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		time.Sleep(delay)
+		_, _ = w.Write([]byte(" body"))
+	}))
+	defer server.Close()
+
+	c := NewChecker(&MockDNS{resolveFunc: func(domain string) (string, error) {
+		time.Sleep(delay)
+		return "127.0.0.1", nil
+	}})
+
+	result, err := c.CheckDomain(&models.Domain{ID: uuid.New(), URL: "http://timings.test:" + serverPort(t, server)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("Expected empty error, got %s", result.Error)
+	}
+	if result.Timings == nil {
+		t.Fatal("Expected timings to be recorded")
+	}
+
+	timings := result.Timings
+	if timings.DNSLookup < delay.Milliseconds() {
+		t.Errorf("Expected DNSLookup >= %d, got %d", delay.Milliseconds(), timings.DNSLookup)
+	}
+	if timings.TimeToFirstByte < delay.Milliseconds() {
+		t.Errorf("Expected TimeToFirstByte >= %d, got %d", delay.Milliseconds(), timings.TimeToFirstByte)
+	}
+	if timings.ContentTransfer < delay.Milliseconds() {
+		t.Errorf("Expected ContentTransfer >= %d, got %d", delay.Milliseconds(), timings.ContentTransfer)
+	}
+	if timings.TLSHandshake != 0 {
+		t.Errorf("Expected no TLS handshake over http, got %d", timings.TLSHandshake)
+	}
+
+	total := timings.DNSLookup + timings.TCPConnect + timings.TimeToFirstByte + timings.ContentTransfer
+	if total > result.ResponseTime+timings.DNSLookup+1 {
+		t.Errorf("Expected phases to fit in the check, got %+v for %dms", *timings, result.ResponseTime)
+	}
+}
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"golang.org/x/net/publicsuffix"
+)
+
+// redirectTracker acompanha os saltos de uma verificação e detecta loops,
+// downgrades de HTTPS para HTTP e redirecionamentos para outros domínios.
+type redirectTracker struct {
+	origin    *url.URL
+	chain     models.RedirectChain
+	seen      map[string]bool
+	redirects int
+}
+
+func newRedirectTracker(origin *url.URL) *redirectTracker {
+	return &redirectTracker{
+		origin: origin,
+		seen:   map[string]bool{normalizeURL(origin): true},
+	}
+}
+
+// record registra a resposta de um salto. next é nil quando a resposta não é
+// um redirecionamento.
+func (t *redirectTracker) record(current *url.URL, resp *http.Response, elapsed time.Duration, next *url.URL) {
+	hop := models.RedirectHop{
+		URL:          current.String(),
+		StatusCode:   resp.StatusCode,
+		ResponseTime: elapsed.Milliseconds(),
+	}
+
+	if next != nil {
+		hop.Location = next.String()
+		t.redirects++
+
+		if current.Scheme == "https" && next.Scheme == "http" {
+			t.chain.Downgrade = true
+		}
+
+		if !sameSite(t.origin.Hostname(), next.Hostname()) {
+			t.chain.CrossDomain = true
+		}
+	}
+
+	t.chain.Hops = append(t.chain.Hops, hop)
+}
+
+// visited informa se a URL já foi requisitada nesta cadeia e a marca como vista.
+func (t *redirectTracker) visited(u *url.URL) bool {
+	key := normalizeURL(u)
+	if t.seen[key] {
+		return true
+	}
+	t.seen[key] = true
+	return false
+}
+
+func (t *redirectTracker) markLoop() {
+	t.chain.Loop = true
+}
+
+// count retorna quantos redirecionamentos foram recebidos
+func (t *redirectTracker) count() int {
+	return t.redirects
+}
+
+// result retorna uma cópia da cadeia registrada
+func (t *redirectTracker) result() *models.RedirectChain {
+	chain := t.chain
+	chain.Hops = append([]models.RedirectHop(nil), t.chain.Hops...)
+	return &chain
+}
+
+// redirectTarget retorna o destino do redirecionamento quando a resposta é um
+// 3xx com cabeçalho Location válido.
+func redirectTarget(current *url.URL, resp *http.Response) (*url.URL, bool) {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, false
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, false
+	}
+
+	next, err := current.Parse(location)
+	if err != nil {
+		return nil, false
+	}
+
+	return next, true
+}
+
+// sameSite compara os domínios registráveis (eTLD+1) de dois hosts, de modo que
+// example.com -> www.example.com não seja considerado cross-domain.
+func sameSite(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == b {
+		return true
+	}
+
+	siteA, errA := publicsuffix.EffectiveTLDPlusOne(a)
+	siteB, errB := publicsuffix.EffectiveTLDPlusOne(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	return siteA == siteB
+}
+
+// normalizeURL gera a chave usada na detecção de loops
+func normalizeURL(u *url.URL) string {
+	n := *u
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	n.Fragment = ""
+	if n.Path == "" {
+		n.Path = "/"
+	}
+	return n.String()
+}
@@ -0,0 +1,211 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// newRedirectServer cria um servidor com rotas de redirecionamento para os testes
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/final", http.StatusFound)
+	})
+	mux.HandleFunc("/final", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "final")
+		_, _ = w.Write([]byte("done"))
+	})
+	mux.HandleFunc("/loop-a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-b", http.StatusFound)
+	})
+	mux.HandleFunc("/loop-b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-a", http.StatusFound)
+	})
+	mux.HandleFunc("/endless/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		target := "http://other.test:" + r.URL.Query().Get("port") + "/final"
+		http.Redirect(w, r, target, http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func localChecker(config *Config) Checker {
+	return NewCheckerWithConfig(&MockDNS{
+		resolveFunc: func(domain string) (string, error) { return "127.0.0.1", nil },
+	}, config)
+}
+
+// TestCheckDomainRedirects testa o acompanhamento de redirecionamentos (white-box)
+func TestCheckDomainRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+	base := "http://redirect.test:" + serverPort(t, server)
+
+	t.Run("Follows Chain", func(t *testing.T) {
+		result, err := localChecker(nil).CheckDomain(&models.Domain{ID: uuid.New(), URL: base + "/start"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Error != "" {
+			t.Errorf("Expected empty error, got %s", result.Error)
+		}
+		if result.StatusCode != http.StatusOK {
+			t.Errorf("Expected final StatusCode 200, got %d", result.StatusCode)
+		}
+		if result.Server != "final" {
+			t.Errorf("Expected Server from final response, got %s", result.Server)
+		}
+		if result.RedirectCount != 2 {
+			t.Errorf("Expected RedirectCount 2, got %d", result.RedirectCount)
+		}
+		if result.RedirectURL != base+"/final" {
+			t.Errorf("Expected RedirectURL %s/final, got %s", base, result.RedirectURL)
+		}
+
+		chain := result.RedirectChain
+		if chain == nil {
+			t.Fatal("Expected RedirectChain to be set")
+		}
+		if len(chain.Hops) != 3 {
+			t.Fatalf("Expected 3 hops, got %d", len(chain.Hops))
+		}
+
+		expected := []struct {
+			url    string
+			status int
+		}{
+			{base + "/start", http.StatusMovedPermanently},
+			{base + "/middle", http.StatusFound},
+			{base + "/final", http.StatusOK},
+		}
+		for i, e := range expected {
+			if chain.Hops[i].URL != e.url || chain.Hops[i].StatusCode != e.status {
+				t.Errorf("Hop %d: expected %s (%d), got %s (%d)",
+					i, e.url, e.status, chain.Hops[i].URL, chain.Hops[i].StatusCode)
+			}
+		}
+		if chain.Loop || chain.Downgrade || chain.CrossDomain {
+			t.Errorf("Expected no anomalies, got %+v", chain)
+		}
+	})
+
+	t.Run("No Redirect", func(t *testing.T) {
+		result, _ := localChecker(nil).CheckDomain(&models.Domain{ID: uuid.New(), URL: base + "/final"})
+
+		if result.RedirectChain != nil {
+			t.Errorf("Expected nil RedirectChain, got %+v", result.RedirectChain)
+		}
+		if result.RedirectURL != "" {
+			t.Errorf("Expected empty RedirectURL, got %s", result.RedirectURL)
+		}
+	})
+
+	t.Run("Loop", func(t *testing.T) {
+		result, _ := localChecker(nil).CheckDomain(&models.Domain{ID: uuid.New(), URL: base + "/loop-a"})
+
+		if result.Error != ErrRedirectLoop.Error() {
+			t.Errorf("Expected loop error, got %q", result.Error)
+		}
+		if result.RedirectChain == nil || !result.RedirectChain.Loop {
+			t.Error("Expected RedirectChain.Loop to be flagged")
+		}
+		if result.RedirectCount != 2 {
+			t.Errorf("Expected RedirectCount 2, got %d", result.RedirectCount)
+		}
+	})
+
+	t.Run("Max Redirects", func(t *testing.T) {
+		result, _ := localChecker(&Config{MaxRedirects: 3}).CheckDomain(&models.Domain{ID: uuid.New(), URL: base + "/endless/"})
+
+		if result.Error != ErrTooManyRedirects.Error() {
+			t.Errorf("Expected too many redirects error, got %q", result.Error)
+		}
+		if result.RedirectCount != 4 {
+			t.Errorf("Expected to stop at the 4th redirect, got %d", result.RedirectCount)
+		}
+		if result.StatusCode != http.StatusTemporaryRedirect {
+			t.Errorf("Expected last StatusCode 307, got %d", result.StatusCode)
+		}
+	})
+
+	t.Run("Cross Domain", func(t *testing.T) {
+		mockDNS := &MockDNS{}
+		var lookups []string
+		mockDNS.resolveFunc = func(domain string) (string, error) {
+			lookups = append(lookups, domain)
+			return "127.0.0.1", nil
+		}
+
+		checkerInstance := NewChecker(mockDNS)
+		result, _ := checkerInstance.CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: base + "/elsewhere?port=" + serverPort(t, server),
+		})
+
+		if result.Error != "" {
+			t.Fatalf("Expected empty error, got %s", result.Error)
+		}
+		if result.RedirectChain == nil || !result.RedirectChain.CrossDomain {
+			t.Error("Expected RedirectChain.CrossDomain to be flagged")
+		}
+		if len(lookups) != 2 || lookups[1] != "other.test" {
+			t.Errorf("Expected redirect host to be resolved through DNSResolver, got %v", lookups)
+		}
+	})
+}
+
+// TestRedirectTrackerDowngrade testa a detecção de HTTPS -> HTTP (white-box)
+func TestRedirectTrackerDowngrade(t *testing.T) {
+	origin, _ := url.Parse("https://secure.example.com/")
+	next, _ := url.Parse("http://secure.example.com/")
+
+	tracker := newRedirectTracker(origin)
+	tracker.record(origin, &http.Response{StatusCode: http.StatusFound}, 0, next)
+
+	chain := tracker.result()
+	if !chain.Downgrade {
+		t.Error("Expected Downgrade to be flagged")
+	}
+	if chain.CrossDomain {
+		t.Error("Expected same host not to be flagged as cross-domain")
+	}
+	if chain.Hops[0].Location != next.String() {
+		t.Errorf("Expected Location %s, got %s", next, chain.Hops[0].Location)
+	}
+}
+
+// TestSameSite testa a comparação de domínios registráveis (white-box)
+func TestSameSite(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "www.example.com", true},
+		{"WWW.Example.com", "api.example.com", true},
+		{"example.com", "example.org", false},
+		{"foo.co.uk", "bar.co.uk", false},
+		{"example.com", "attacker.net", false},
+	}
+
+	for _, tt := range tests {
+		if got := sameSite(tt.a, tt.b); got != tt.expected {
+			t.Errorf("sameSite(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
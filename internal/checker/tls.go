@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// tlsInspector captura o certificado apresentado pelo servidor e faz a
+// validação manualmente, para que os detalhes fiquem disponíveis mesmo quando
+// o certificado é inválido.
+type tlsInspector struct {
+	roots *x509.CertPool
+	info  *models.TLSInfo
+}
+
+// clientConfig retorna a configuração TLS usada pelo transporte. A verificação
+// padrão é desativada e substituída por verifyConnection.
+func (i *tlsInspector) clientConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyConnection:   i.verifyConnection,
+	}
+}
+
+func (i *tlsInspector) verifyConnection(cs tls.ConnectionState) error {
+	info, err := inspectTLS(cs, i.roots, time.Now())
+
+	// Registra apenas o primeiro handshake, que pertence ao host verificado
+	if i.info == nil {
+		i.info = info
+	}
+
+	return err
+}
+
+// inspectTLS extrai os dados da sessão e valida cadeia e hostname separadamente,
+// retornando o erro classificado da primeira falha encontrada.
+func inspectTLS(cs tls.ConnectionState, roots *x509.CertPool, now time.Time) (*models.TLSInfo, error) {
+	info := &models.TLSInfo{
+		Version:     tls.VersionName(cs.Version),
+		CipherSuite: tls.CipherSuiteName(cs.CipherSuite),
+	}
+
+	if len(cs.PeerCertificates) == 0 {
+		return info, fmt.Errorf("%w: no peer certificate", ErrTLSUntrustedChain)
+	}
+
+	leaf := cs.PeerCertificates[0]
+	info.Subject = leaf.Subject.String()
+	info.SANs = certificateNames(leaf)
+	info.Issuer = leaf.Issuer.String()
+	info.NotBefore = leaf.NotBefore
+	info.NotAfter = leaf.NotAfter
+	info.DaysToExpiry = int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, chainErr := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	info.ChainValid = chainErr == nil
+
+	hostErr := leaf.VerifyHostname(cs.ServerName)
+	info.HostnameValid = hostErr == nil
+
+	switch {
+	case chainErr != nil:
+		return info, classifyChainError(leaf, chainErr, now)
+	case hostErr != nil:
+		return info, fmt.Errorf("%w: %v", ErrTLSHostnameMismatch, hostErr)
+	}
+
+	return info, nil
+}
+
+// classifyChainError distingue certificados fora da validade de cadeias não confiáveis
+func classifyChainError(leaf *x509.Certificate, err error, now time.Time) error {
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+		if now.Before(leaf.NotBefore) {
+			return fmt.Errorf("%w: %v", ErrTLSNotYetValid, err)
+		}
+		return fmt.Errorf("%w: %v", ErrTLSExpired, err)
+	}
+
+	return fmt.Errorf("%w: %v", ErrTLSUntrustedChain, err)
+}
+
+// certificateNames retorna os DNS names e IPs declarados no SAN do certificado
+func certificateNames(cert *x509.Certificate) []string {
+	names := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
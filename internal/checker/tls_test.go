@@ -0,0 +1,206 @@
+package checker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// newTestCertificate gera um certificado autoassinado para os testes de TLS
+func newTestCertificate(t *testing.T, host string, notBefore, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: host},
+		Issuer:                pkix.Name{CommonName: host},
+		DNSNames:              []string{host},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+// TestCheckDomainTLS testa a inspeção TLS de ponta a ponta (white-box)
+func TestCheckDomainTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	defer server.Close()
+
+	// O certificado do httptest é válido para example.com e 127.0.0.1
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+	port := serverPort(t, server)
+
+	t.Run("Valid Certificate", func(t *testing.T) {
+		result, err := localChecker(&Config{RootCAs: trusted}).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: "https://example.com:" + port,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Error != "" {
+			t.Fatalf("Expected empty error, got %s", result.Error)
+		}
+		if result.StatusCode != http.StatusOK {
+			t.Errorf("Expected StatusCode 200, got %d", result.StatusCode)
+		}
+
+		info := result.TLS
+		if info == nil {
+			t.Fatal("Expected TLS info to be set")
+		}
+		if !info.ChainValid || !info.HostnameValid {
+			t.Errorf("Expected valid chain and hostname, got %+v", info)
+		}
+		if info.Version == "" || info.CipherSuite == "" {
+			t.Errorf("Expected negotiated version and cipher, got %q / %q", info.Version, info.CipherSuite)
+		}
+		if info.NotAfter != server.Certificate().NotAfter {
+			t.Errorf("Expected NotAfter %v, got %v", server.Certificate().NotAfter, info.NotAfter)
+		}
+		if info.DaysToExpiry <= 0 {
+			t.Errorf("Expected positive DaysToExpiry, got %d", info.DaysToExpiry)
+		}
+		if len(info.SANs) == 0 {
+			t.Error("Expected SANs to be recorded")
+		}
+	})
+
+	t.Run("Hostname Mismatch", func(t *testing.T) {
+		result, _ := localChecker(&Config{RootCAs: trusted}).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: "https://mismatch.test:" + port,
+		})
+
+		if result.ErrorKind != models.ErrorKindTLSHostnameMismatch {
+			t.Errorf("Expected ErrorKind %s, got %q (%s)", models.ErrorKindTLSHostnameMismatch, result.ErrorKind, result.Error)
+		}
+		if result.TLS == nil || result.TLS.HostnameValid || !result.TLS.ChainValid {
+			t.Errorf("Expected TLS info with invalid hostname and valid chain, got %+v", result.TLS)
+		}
+		if result.StatusCode != 0 {
+			t.Errorf("Expected no HTTP response, got %d", result.StatusCode)
+		}
+	})
+
+	t.Run("Untrusted Chain", func(t *testing.T) {
+		result, _ := localChecker(&Config{RootCAs: x509.NewCertPool()}).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: "https://example.com:" + port,
+		})
+
+		if result.ErrorKind != models.ErrorKindTLSUntrustedChain {
+			t.Errorf("Expected ErrorKind %s, got %q (%s)", models.ErrorKindTLSUntrustedChain, result.ErrorKind, result.Error)
+		}
+		if result.TLS == nil || result.TLS.ChainValid {
+			t.Errorf("Expected TLS info with invalid chain, got %+v", result.TLS)
+		}
+	})
+
+	t.Run("Plain HTTP", func(t *testing.T) {
+		plain := httptest.NewServer(http.NotFoundHandler())
+		defer plain.Close()
+
+		result, _ := localChecker(nil).CheckDomain(&models.Domain{
+			ID:  uuid.New(),
+			URL: "http://plain.test:" + serverPort(t, plain),
+		})
+
+		if result.TLS != nil {
+			t.Errorf("Expected no TLS info for plain HTTP, got %+v", result.TLS)
+		}
+	})
+}
+
+// TestInspectTLSValidity testa a classificação de certificados fora da validade (white-box)
+func TestInspectTLSValidity(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		notAfter  time.Time
+		expected  error
+		days      int
+	}{
+		{"Valid", now.Add(-time.Hour), now.Add(72*time.Hour + time.Minute), nil, 3},
+		{"Expired", now.Add(-48 * time.Hour), now.Add(-24*time.Hour - time.Minute), ErrTLSExpired, -2},
+		{"Not Yet Valid", now.Add(24 * time.Hour), now.Add(96*time.Hour + time.Minute), ErrTLSNotYetValid, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := newTestCertificate(t, "cert.test", tt.notBefore, tt.notAfter)
+			roots := x509.NewCertPool()
+			roots.AddCert(cert)
+
+			info, err := inspectTLS(tls.ConnectionState{
+				Version:          tls.VersionTLS13,
+				CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+				ServerName:       "cert.test",
+				PeerCertificates: []*x509.Certificate{cert},
+			}, roots, now)
+
+			if !errors.Is(err, tt.expected) && !(tt.expected == nil && err == nil) {
+				t.Errorf("Expected error %v, got %v", tt.expected, err)
+			}
+			if info.DaysToExpiry != tt.days {
+				t.Errorf("Expected DaysToExpiry %d, got %d", tt.days, info.DaysToExpiry)
+			}
+			if info.Version != "TLS 1.3" {
+				t.Errorf("Expected version TLS 1.3, got %s", info.Version)
+			}
+			if info.Subject != "CN=cert.test" {
+				t.Errorf("Expected subject CN=cert.test, got %s", info.Subject)
+			}
+		})
+	}
+}
+
+// TestClassifyError testa o mapeamento de erros para ErrorKind (white-box)
+func TestClassifyError(t *testing.T) {
+	wrapped := errors.Join(errors.New("handshake failed"), ErrTLSExpired)
+
+	if kind := classifyError(wrapped); kind != models.ErrorKindTLSExpired {
+		t.Errorf("Expected %s, got %s", models.ErrorKindTLSExpired, kind)
+	}
+
+	if kind := classifyError(errors.New("unknown")); kind != "" {
+		t.Errorf("Expected empty kind, got %s", kind)
+	}
+}
@@ -0,0 +1,573 @@
+package dns
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheEntries é o tamanho máximo do cache quando CacheConfig.MaxEntries é zero
+	DefaultCacheEntries = 10000
+	// DefaultCacheTTL é usado quando o resolver não informa o TTL das respostas
+	DefaultCacheTTL = time.Minute
+	// DefaultNegativeTTL é usado para respostas negativas sem SOA
+	DefaultNegativeTTL = time.Minute
+	// maxNegativeTTL é o limite recomendado para cache negativo (RFC 2308, seção 5)
+	maxNegativeTTL = 3 * time.Hour
+)
+
+// CacheConfig define os limites do cache de respostas
+type CacheConfig struct {
+	MaxEntries int `json:"max_entries,omitempty"`
+	// DefaultTTL vale para resolvers que não informam TTL, como o do sistema
+	DefaultTTL time.Duration `json:"default_ttl,omitempty"`
+	// MinTTL e MaxTTL limitam o TTL recebido; MaxTTL zero não limita
+	MinTTL time.Duration `json:"min_ttl,omitempty"`
+	MaxTTL time.Duration `json:"max_ttl,omitempty"`
+	// NegativeTTL vale para NXDOMAIN e respostas vazias sem SOA. Use um valor
+	// negativo para não guardar respostas negativas.
+	NegativeTTL time.Duration `json:"negative_ttl,omitempty"`
+}
+
+// CacheStats resume o uso do cache
+type CacheStats struct {
+	Hits uint64 `json:"hits"`
+	// NegativeHits é a parte de Hits respondida com um erro ou uma resposta
+	// sem registros (NODATA) guardados
+	NegativeHits uint64 `json:"negative_hits"`
+	Misses       uint64 `json:"misses"`
+	// Shared é a parte de Misses respondida por uma consulta já em andamento
+	Shared    uint64 `json:"shared"`
+	Bypassed  uint64 `json:"bypassed"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// addressResolver é implementado pelos resolvers que conhecem o TTL dos
+// endereços; os demais usam CacheConfig.DefaultTTL
+type addressResolver interface {
+	resolveAddresses(ctx context.Context, domain string) (*Records, error)
+}
+
+// allRecordTypes são os tipos considerados no TTL das respostas guardadas
+var allRecordTypes = []RecordType{RecordTypeA, RecordTypeAAAA, RecordTypeCNAME, RecordTypeMX,
+	RecordTypeNS, RecordTypeTXT, RecordTypeSOA, RecordTypeCAA}
+
+// recordTypeResolver é implementado pelos resolvers que informam o TTL
+// negativo das respostas de LookupType, usado para guardar respostas sem
+// registros (NODATA)
+type recordTypeResolver interface {
+	lookupRecordType(ctx context.Context, domain string, recordType RecordType) (*Records, uint32, error)
+}
+
+type cacheKind uint8
+
+const (
+	cacheAddresses cacheKind = iota
+	cacheRecords
+	cacheRecordType
+)
+
+type cacheKey struct {
+	kind   cacheKind
+	domain string
+	// recordType identifica as entradas de cacheRecordType
+	recordType RecordType
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	ips     []string
+	records *Records
+	err     error
+	// negative indica uma resposta sem registros (NODATA) guardada sem erro
+	negative bool
+	expires  time.Time
+}
+
+// flight é uma consulta em andamento ao resolver, compartilhada pelas faltas
+// simultâneas da mesma chave
+type flight struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// cachingDNS guarda as respostas de resolver até o fim do TTL, descartando as
+// menos usadas quando atinge MaxEntries
+type cachingDNS struct {
+	resolver DNS
+	config   CacheConfig
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+	flights map[cacheKey]*flight
+	stats   CacheStats
+}
+
+var _ CachingDNS = (*cachingDNS)(nil)
+
+// NewCachingDNS envolve resolver com um cache de respostas. Campos não
+// informados em config usam os valores padrão.
+func NewCachingDNS(resolver DNS, config *CacheConfig) CachingDNS {
+	return &cachingDNS{
+		resolver: resolver,
+		config:   config.withDefaults(),
+		now:      time.Now,
+		entries:  make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		flights:  make(map[cacheKey]*flight),
+	}
+}
+
+func (c *CacheConfig) withDefaults() CacheConfig {
+	var config CacheConfig
+	if c != nil {
+		config = *c
+	}
+
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultCacheEntries
+	}
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = DefaultCacheTTL
+	}
+	if config.NegativeTTL == 0 {
+		config.NegativeTTL = DefaultNegativeTTL
+	}
+
+	return config
+}
+
+func (c *cachingDNS) Resolve(domain string) (string, error) {
+	return c.ResolveContext(context.Background(), domain)
+}
+
+func (c *cachingDNS) ResolveContext(ctx context.Context, domain string) (string, error) {
+	ips, err := c.resolveAll(ctx, domain, false)
+	if err != nil {
+		return "", err
+	}
+	return ips[0], nil
+}
+
+func (c *cachingDNS) ResolveAll(domain string) ([]string, error) {
+	return c.ResolveAllContext(context.Background(), domain)
+}
+
+func (c *cachingDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	return c.resolveAll(ctx, domain, false)
+}
+
+func (c *cachingDNS) Lookup(domain string) (*Records, error) {
+	return c.LookupContext(context.Background(), domain)
+}
+
+func (c *cachingDNS) LookupContext(ctx context.Context, domain string) (*Records, error) {
+	return c.lookup(ctx, domain, false)
+}
+
+func (c *cachingDNS) LookupType(domain string, recordType RecordType) (*Records, error) {
+	return c.LookupTypeContext(context.Background(), domain, recordType)
+}
+
+func (c *cachingDNS) LookupTypeContext(ctx context.Context, domain string, recordType RecordType) (*Records, error) {
+	return c.lookupType(ctx, domain, recordType, false)
+}
+
+// Stats retorna os contadores acumulados do cache
+func (c *cachingDNS) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Bypass retorna um DNS que sempre consulta o resolver e atualiza o cache com
+// a resposta obtida
+func (c *cachingDNS) Bypass() DNS {
+	return &bypassDNS{cache: c}
+}
+
+// Purge descarta todas as respostas guardadas
+func (c *cachingDNS) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[cacheKey]*list.Element)
+	c.lru.Init()
+}
+
+// resolveAll responde do cache quando possível. Erros causados por ctx não
+// são guardados, pois não dizem nada sobre o nome consultado.
+func (c *cachingDNS) resolveAll(ctx context.Context, domain string, bypass bool) ([]string, error) {
+	key := cacheKey{kind: cacheAddresses, domain: cacheDomain(domain)}
+	if !bypass {
+		if entry, ok := c.get(key); ok {
+			return cloneStrings(entry.ips), entry.err
+		}
+	}
+
+	ips, err := share(ctx, c, key, bypass, func() ([]string, error) {
+		return c.fetchAddresses(ctx, key, domain)
+	})
+	return cloneStrings(ips), err
+}
+
+// fetchAddresses consulta os endereços no resolver e guarda a resposta
+func (c *cachingDNS) fetchAddresses(ctx context.Context, key cacheKey, domain string) ([]string, error) {
+	var (
+		ips []string
+		ttl time.Duration
+		err error
+	)
+	if resolver, ok := c.resolver.(addressResolver); ok {
+		var records *Records
+		if records, err = resolver.resolveAddresses(ctx, domain); err == nil {
+			ips = records.IPs()
+			ttl = c.positiveTTL(records.minTTL(RecordTypeA, RecordTypeAAAA, RecordTypeCNAME))
+		}
+	} else if ips, err = c.resolver.ResolveAllContext(ctx, domain); err == nil {
+		ttl = c.positiveTTL(c.config.DefaultTTL, true)
+	}
+
+	if err != nil {
+		c.putNegative(key, err)
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, &LookupError{Domain: trimDot(domain), Kind: ErrNoAnswer}
+	}
+
+	c.put(&cacheEntry{key: key, ips: cloneStrings(ips)}, ttl)
+	return ips, nil
+}
+
+func (c *cachingDNS) lookup(ctx context.Context, domain string, bypass bool) (*Records, error) {
+	key := cacheKey{kind: cacheRecords, domain: cacheDomain(domain)}
+	if !bypass {
+		if entry, ok := c.get(key); ok {
+			return entry.records.clone(), entry.err
+		}
+	}
+
+	records, err := share(ctx, c, key, bypass, func() (*Records, error) {
+		return c.fetchRecords(ctx, key, domain)
+	})
+	return records.clone(), err
+}
+
+// fetchRecords consulta todos os tipos no resolver e guarda a resposta
+func (c *cachingDNS) fetchRecords(ctx context.Context, key cacheKey, domain string) (*Records, error) {
+	records, err := c.resolver.LookupContext(ctx, domain)
+	if err != nil {
+		// Respostas parciais não são guardadas
+		if records == nil {
+			c.putNegative(key, err)
+		}
+		return records, err
+	}
+
+	ttl := c.positiveTTL(records.minTTL(allRecordTypes...))
+	c.put(&cacheEntry{key: key, records: records.clone()}, ttl)
+	return records, nil
+}
+
+func (c *cachingDNS) lookupType(ctx context.Context, domain string, recordType RecordType, bypass bool) (*Records, error) {
+	key := cacheKey{kind: cacheRecordType, domain: cacheDomain(domain), recordType: recordType}
+	if !bypass {
+		if entry, ok := c.get(key); ok {
+			return entry.records.clone(), entry.err
+		}
+	}
+
+	records, err := share(ctx, c, key, bypass, func() (*Records, error) {
+		return c.fetchRecordType(ctx, key, domain, recordType)
+	})
+	return records.clone(), err
+}
+
+// fetchRecordType consulta um tipo no resolver e guarda a resposta. Respostas
+// sem registros (NODATA) são guardadas pelo TTL negativo do SOA (RFC 2308).
+func (c *cachingDNS) fetchRecordType(ctx context.Context, key cacheKey, domain string, recordType RecordType) (*Records, error) {
+	var (
+		records *Records
+		negTTL  uint32
+		err     error
+	)
+	if resolver, ok := c.resolver.(recordTypeResolver); ok {
+		records, negTTL, err = resolver.lookupRecordType(ctx, domain, recordType)
+	} else {
+		records, err = c.resolver.LookupTypeContext(ctx, domain, recordType)
+	}
+	if err != nil {
+		c.putNegative(key, err)
+		return nil, err
+	}
+
+	ttl, ok := records.minTTL(allRecordTypes...)
+	if !ok {
+		if ttl, ok := c.negativeTTL(negTTL); ok {
+			c.put(&cacheEntry{key: key, records: records.clone(), negative: true}, ttl)
+		}
+		return records, nil
+	}
+
+	c.put(&cacheEntry{key: key, records: records.clone()}, c.positiveTTL(ttl, true))
+	return records, nil
+}
+
+// share executa fetch uma única vez para as faltas simultâneas de key: as
+// demais esperam e recebem o mesmo resultado, que não deve ser alterado.
+// Consultas com bypass não são compartilhadas.
+func share[T any](ctx context.Context, c *cachingDNS, key cacheKey, bypass bool, fetch func() (T, error)) (T, error) {
+	if bypass {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.stats.Shared++
+		c.mu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+
+		// A consulta compartilhada foi interrompida pelo ctx de quem a fez
+		if ctxErr(f.err) && ctx.Err() == nil {
+			return fetch()
+		}
+		value, _ := f.value.(T)
+		return value, f.err
+	}
+
+	f := &flight{done: make(chan struct{})}
+	c.flights[key] = f
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.flights, key)
+		c.mu.Unlock()
+		close(f.done)
+	}()
+
+	value, err := fetch()
+	f.value, f.err = value, err
+	return value, err
+}
+
+// ctxErr informa se err foi causado pelo cancelamento ou prazo de um ctx
+func ctxErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// get retorna a entrada válida de key, contabilizando acerto ou falta
+func (c *cachingDNS) get(key cacheKey) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if c.now().Before(entry.expires) {
+			c.lru.MoveToFront(elem)
+			c.stats.Hits++
+			if entry.err != nil || entry.negative {
+				c.stats.NegativeHits++
+			}
+			return entry, true
+		}
+		c.remove(elem)
+	}
+
+	c.stats.Misses++
+	return nil, false
+}
+
+// put guarda entry por ttl, descartando as entradas menos usadas se necessário
+func (c *cachingDNS) put(entry *cacheEntry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.remove(elem)
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	entry.expires = c.now().Add(ttl)
+	c.entries[entry.key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.config.MaxEntries {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// putNegative guarda NXDOMAIN e respostas vazias (RFC 2308). Falhas do
+// servidor e de transporte não são guardadas.
+func (c *cachingDNS) putNegative(key cacheKey, err error) {
+	if !errors.Is(err, ErrNXDomain) && !errors.Is(err, ErrNoAnswer) {
+		return
+	}
+
+	var soaTTL uint32
+	var lookupErr *LookupError
+	if errors.As(err, &lookupErr) {
+		soaTTL = lookupErr.TTL
+	}
+
+	if ttl, ok := c.negativeTTL(soaTTL); ok {
+		c.put(&cacheEntry{key: key, err: err}, ttl)
+	}
+}
+
+// negativeTTL retorna por quanto tempo guardar uma resposta negativa: o TTL
+// do SOA, em segundos, ou NegativeTTL quando ele não foi informado. Retorna
+// false quando o cache negativo está desligado.
+func (c *cachingDNS) negativeTTL(soaTTL uint32) (time.Duration, bool) {
+	if c.config.NegativeTTL < 0 {
+		return 0, false
+	}
+
+	ttl := c.config.NegativeTTL
+	if soaTTL > 0 {
+		ttl = time.Duration(soaTTL) * time.Second
+	}
+	return min(ttl, maxNegativeTTL), true
+}
+
+func (c *cachingDNS) remove(elem *list.Element) {
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+	c.lru.Remove(elem)
+}
+
+// positiveTTL aplica MinTTL e MaxTTL ao TTL recebido. Respostas sem registros
+// não têm TTL e não são guardadas.
+func (c *cachingDNS) positiveTTL(ttl time.Duration, ok bool) time.Duration {
+	if !ok {
+		return 0
+	}
+	if ttl < c.config.MinTTL {
+		ttl = c.config.MinTTL
+	}
+	if c.config.MaxTTL > 0 && ttl > c.config.MaxTTL {
+		ttl = c.config.MaxTTL
+	}
+	return ttl
+}
+
+// bypassDNS consulta sempre o resolver do cache, atualizando as entradas
+type bypassDNS struct {
+	cache *cachingDNS
+}
+
+var _ DNS = (*bypassDNS)(nil)
+
+func (b *bypassDNS) Resolve(domain string) (string, error) {
+	return b.ResolveContext(context.Background(), domain)
+}
+
+func (b *bypassDNS) ResolveContext(ctx context.Context, domain string) (string, error) {
+	ips, err := b.ResolveAllContext(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	return ips[0], nil
+}
+
+func (b *bypassDNS) ResolveAll(domain string) ([]string, error) {
+	return b.ResolveAllContext(context.Background(), domain)
+}
+
+func (b *bypassDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	b.cache.countBypass()
+	return b.cache.resolveAll(ctx, domain, true)
+}
+
+func (b *bypassDNS) Lookup(domain string) (*Records, error) {
+	return b.LookupContext(context.Background(), domain)
+}
+
+func (b *bypassDNS) LookupContext(ctx context.Context, domain string) (*Records, error) {
+	b.cache.countBypass()
+	return b.cache.lookup(ctx, domain, true)
+}
+
+func (b *bypassDNS) LookupType(domain string, recordType RecordType) (*Records, error) {
+	return b.LookupTypeContext(context.Background(), domain, recordType)
+}
+
+func (b *bypassDNS) LookupTypeContext(ctx context.Context, domain string, recordType RecordType) (*Records, error) {
+	b.cache.countBypass()
+	return b.cache.lookupType(ctx, domain, recordType, true)
+}
+
+func (c *cachingDNS) countBypass() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Bypassed++
+}
+
+// minTTL retorna o menor TTL entre os registros dos tipos informados
+func (r *Records) minTTL(types ...RecordType) (time.Duration, bool) {
+	var (
+		ttl   uint32
+		found bool
+	)
+	for _, t := range types {
+		values, typeTTL, err := r.Values(t)
+		if err != nil || len(values) == 0 {
+			continue
+		}
+		if !found || typeTTL < ttl {
+			ttl, found = typeTTL, true
+		}
+	}
+	return time.Duration(ttl) * time.Second, found
+}
+
+// clone retorna uma cópia de r que não compartilha slices com o original
+func (r *Records) clone() *Records {
+	if r == nil {
+		return nil
+	}
+
+	c := *r
+	c.A = append([]IPRecord(nil), r.A...)
+	c.AAAA = append([]IPRecord(nil), r.AAAA...)
+	c.CNAME = append([]CNAMERecord(nil), r.CNAME...)
+	c.MX = append([]MXRecord(nil), r.MX...)
+	c.NS = append([]NSRecord(nil), r.NS...)
+	c.TXT = append([]TXTRecord(nil), r.TXT...)
+	c.CAA = append([]CAARecord(nil), r.CAA...)
+	if r.SOA != nil {
+		soa := *r.SOA
+		c.SOA = &soa
+	}
+	return &c
+}
+
+func cacheDomain(domain string) string {
+	return strings.ToLower(trimDot(domain))
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
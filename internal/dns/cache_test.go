@@ -0,0 +1,499 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeClock permite avançar o tempo do cache nos testes
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newTestCache cria um cache com relógio controlado (white-box)
+func newTestCache(resolver DNS, config *CacheConfig) (*cachingDNS, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewCachingDNS(resolver, config).(*cachingDNS)
+	cache.now = clock.Now
+	return cache, clock
+}
+
+// countingDNS é um resolver sem TTL que conta as consultas
+type countingDNS struct {
+	calls map[string]int
+	err   error
+}
+
+func (d *countingDNS) Resolve(domain string) (string, error) {
+	ips, err := d.ResolveAll(domain)
+	if err != nil {
+		return "", err
+	}
+	return ips[0], nil
+}
+
+func (d *countingDNS) ResolveAll(domain string) ([]string, error) {
+	d.calls[domain]++
+	if d.err != nil {
+		return nil, d.err
+	}
+	return []string{fmt.Sprintf("192.0.2.%d", d.calls[domain])}, nil
+}
+
+func (d *countingDNS) Lookup(domain string) (*Records, error) {
+	d.calls[domain]++
+	return &Records{Name: domain}, d.err
+}
+
+func (d *countingDNS) LookupType(domain string, _ RecordType) (*Records, error) {
+	return d.Lookup(domain)
+}
+
+func (d *countingDNS) ResolveContext(_ context.Context, domain string) (string, error) {
+	return d.Resolve(domain)
+}
+
+func (d *countingDNS) ResolveAllContext(_ context.Context, domain string) ([]string, error) {
+	return d.ResolveAll(domain)
+}
+
+func (d *countingDNS) LookupContext(_ context.Context, domain string) (*Records, error) {
+	return d.Lookup(domain)
+}
+
+func (d *countingDNS) LookupTypeContext(_ context.Context, domain string, recordType RecordType) (*Records, error) {
+	return d.LookupType(domain, recordType)
+}
+
+// TestCachingDNSHonorsTTL testa que as respostas valem pelo menor TTL da cadeia
+func TestCachingDNSHonorsTTL(t *testing.T) {
+	server := newTestServer(t, exampleZone().handler)
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, clock := newTestCache(resolver, nil)
+
+	first, err := cache.ResolveAll("www.example.test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	queries := server.queryCount()
+
+	// O menor TTL entre CNAMEs e endereços é 30s
+	clock.advance(29 * time.Second)
+	second, err := cache.ResolveAll("WWW.example.test.")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if server.queryCount() != queries {
+		t.Errorf("Expected cached answer, server received %d new queries", server.queryCount()-queries)
+	}
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("Expected %v, got %v", first, second)
+	}
+
+	ip, err := cache.Resolve("www.example.test")
+	if err != nil || ip != first[0] {
+		t.Errorf("Expected %s from cache, got %s (%v)", first[0], ip, err)
+	}
+
+	clock.advance(2 * time.Second)
+	if _, err := cache.ResolveAll("www.example.test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if server.queryCount() == queries {
+		t.Error("Expected expired entry to be fetched again")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("Expected 2 hits, 2 misses and 1 entry, got %+v", stats)
+	}
+}
+
+// TestCachingDNSLookup testa o cache de Lookup e o isolamento das cópias
+func TestCachingDNSLookup(t *testing.T) {
+	server := newTestServer(t, exampleZone().handler)
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, clock := newTestCache(resolver, &CacheConfig{MaxTTL: 10 * time.Second})
+
+	records, err := cache.Lookup("www.example.test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	records.A[0].IP = "changed"
+	queries := server.queryCount()
+
+	clock.advance(9 * time.Second)
+	cached, err := cache.Lookup("www.example.test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if server.queryCount() != queries {
+		t.Error("Expected Lookup to be served from cache")
+	}
+	if cached.A[0].IP != "192.0.2.10" {
+		t.Errorf("Expected cached records to be isolated from callers, got %s", cached.A[0].IP)
+	}
+
+	// LookupType tem entradas próprias, separadas por tipo
+	queries = server.queryCount()
+	for range 2 {
+		mx, err := cache.LookupType("www.example.test", RecordTypeMX)
+		if err != nil || len(mx.MX) != 2 {
+			t.Fatalf("Expected MX records, got %+v, %v", mx, err)
+		}
+	}
+	if server.queryCount()-queries != 1 {
+		t.Errorf("Expected LookupType to query once, got %d queries", server.queryCount()-queries)
+	}
+
+	// MaxTTL limita o TTL de 30s recebido
+	clock.advance(time.Second)
+	if _, err := cache.Lookup("www.example.test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if server.queryCount() == queries {
+		t.Error("Expected MaxTTL to expire the entry")
+	}
+}
+
+// TestCachingDNSNegative testa o cache negativo com o TTL do SOA (RFC 2308)
+func TestCachingDNSNegative(t *testing.T) {
+	handler := func(q dnsmessage.Question) dnsmessage.Message {
+		return dnsmessage.Message{
+			Header: dnsmessage.Header{RCode: dnsmessage.RCodeNameError},
+			Authorities: []dnsmessage.Resource{
+				rr("example.test", 600, &dnsmessage.SOAResource{
+					NS: mustName("ns1.example.test"), MBox: mustName("hostmaster.example.test"),
+					Serial: 1, MinTTL: 120,
+				}),
+			},
+		}
+	}
+	server := newTestServer(t, handler)
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, clock := newTestCache(resolver, nil)
+
+	_, err = cache.ResolveAll("missing.example.test")
+	var lookupErr *LookupError
+	if !errors.As(err, &lookupErr) || lookupErr.TTL != 120 {
+		t.Fatalf("Expected NXDOMAIN with negative TTL 120, got %v", err)
+	}
+	queries := server.queryCount()
+
+	clock.advance(119 * time.Second)
+	if _, err := cache.ResolveAll("missing.example.test"); !errors.Is(err, ErrNXDomain) {
+		t.Errorf("Expected cached NXDOMAIN, got %v", err)
+	}
+	if server.queryCount() != queries {
+		t.Error("Expected NXDOMAIN to be served from cache")
+	}
+
+	clock.advance(time.Second)
+	if _, err := cache.ResolveAll("missing.example.test"); !errors.Is(err, ErrNXDomain) {
+		t.Errorf("Expected NXDOMAIN, got %v", err)
+	}
+	if server.queryCount() == queries {
+		t.Error("Expected negative entry to expire after the SOA minimum")
+	}
+
+	if stats := cache.Stats(); stats.NegativeHits != 1 {
+		t.Errorf("Expected 1 negative hit, got %+v", stats)
+	}
+}
+
+// TestCachingDNSNoData testa que LookupType guarda respostas sem registros
+// (NODATA) pelo TTL negativo do SOA (RFC 2308)
+func TestCachingDNSNoData(t *testing.T) {
+	handler := func(q dnsmessage.Question) dnsmessage.Message {
+		return dnsmessage.Message{
+			Authorities: []dnsmessage.Resource{
+				rr("example.test", 600, &dnsmessage.SOAResource{
+					NS: mustName("ns1.example.test"), MBox: mustName("hostmaster.example.test"),
+					Serial: 1, MinTTL: 30,
+				}),
+			},
+		}
+	}
+	server := newTestServer(t, handler)
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, clock := newTestCache(resolver, nil)
+
+	records, err := cache.LookupType("www.example.test", RecordTypeMX)
+	if err != nil || len(records.MX) != 0 {
+		t.Fatalf("Expected empty MX records, got %+v and %v", records, err)
+	}
+	queries := server.queryCount()
+
+	clock.advance(29 * time.Second)
+	if records, err := cache.LookupType("www.example.test", RecordTypeMX); err != nil || records == nil {
+		t.Errorf("Expected cached NODATA, got %+v and %v", records, err)
+	}
+	if server.queryCount() != queries {
+		t.Error("Expected NODATA to be served from cache")
+	}
+
+	clock.advance(time.Second)
+	if _, err := cache.LookupType("www.example.test", RecordTypeMX); err != nil {
+		t.Fatal(err)
+	}
+	if server.queryCount() == queries {
+		t.Error("Expected NODATA entry to expire after the SOA minimum")
+	}
+
+	if stats := cache.Stats(); stats.NegativeHits != 1 {
+		t.Errorf("Expected 1 negative hit, got %+v", stats)
+	}
+}
+
+// blockingDNS segura as consultas de Lookup até release ser fechado
+type blockingDNS struct {
+	*countingDNS
+	release chan struct{}
+	lookups atomic.Int32
+}
+
+func (d *blockingDNS) LookupContext(ctx context.Context, domain string) (*Records, error) {
+	d.lookups.Add(1)
+	select {
+	case <-d.release:
+	case <-ctx.Done():
+		return nil, &LookupError{Domain: domain, Kind: ErrTimeout, Err: ctx.Err()}
+	}
+	return &Records{Name: domain, A: []IPRecord{{IP: "192.0.2.1", TTL: 60}}}, nil
+}
+
+// TestCachingDNSSharedMisses testa que faltas simultâneas da mesma chave
+// fazem uma única consulta ao resolver
+func TestCachingDNSSharedMisses(t *testing.T) {
+	t.Run("Single Query", func(t *testing.T) {
+		resolver := &blockingDNS{countingDNS: &countingDNS{calls: map[string]int{}}, release: make(chan struct{})}
+		cache, _ := newTestCache(resolver, nil)
+
+		const callers = 10
+		results := make(chan *Records, callers)
+		var wg sync.WaitGroup
+		for range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				records, err := cache.Lookup("a.test")
+				if err != nil {
+					t.Error(err)
+				}
+				results <- records
+			}()
+		}
+
+		for cache.Stats().Shared < callers-1 {
+			time.Sleep(time.Millisecond)
+		}
+		close(resolver.release)
+		wg.Wait()
+		close(results)
+
+		if n := resolver.lookups.Load(); n != 1 {
+			t.Errorf("Expected 1 resolver query, got %d", n)
+		}
+
+		// Cada chamador recebe a própria cópia
+		seen := map[*Records]bool{}
+		for records := range results {
+			if records == nil || len(records.A) != 1 || seen[records] {
+				t.Errorf("Expected a distinct copy of the records, got %+v", records)
+			}
+			seen[records] = true
+		}
+	})
+
+	t.Run("Leader Canceled", func(t *testing.T) {
+		resolver := &blockingDNS{countingDNS: &countingDNS{calls: map[string]int{}}, release: make(chan struct{})}
+		cache, _ := newTestCache(resolver, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leaderDone := make(chan error)
+		go func() {
+			_, err := cache.LookupContext(ctx, "a.test")
+			leaderDone <- err
+		}()
+		for resolver.lookups.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		followerDone := make(chan error)
+		go func() {
+			_, err := cache.Lookup("a.test")
+			followerDone <- err
+		}()
+		for cache.Stats().Shared == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// O cancelamento de quem fez a consulta não vale para quem esperava
+		cancel()
+		if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for the leader, got %v", err)
+		}
+		close(resolver.release)
+		if err := <-followerDone; err != nil {
+			t.Errorf("Expected the follower to query again, got %v", err)
+		}
+		if n := resolver.lookups.Load(); n != 2 {
+			t.Errorf("Expected 2 resolver queries, got %d", n)
+		}
+	})
+}
+
+// TestCachingDNSFailures testa que falhas do servidor não são guardadas
+func TestCachingDNSFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		config *CacheConfig
+		calls  int
+	}{
+		{"NXDOMAIN Default TTL", &LookupError{Domain: "a.test", Kind: ErrNXDomain}, nil, 1},
+		{"Negative Cache Disabled", &LookupError{Domain: "a.test", Kind: ErrNXDomain}, &CacheConfig{NegativeTTL: -1}, 2},
+		{"SERVFAIL", &LookupError{Domain: "a.test", Kind: ErrServFail}, nil, 2},
+		{"Timeout", &LookupError{Domain: "a.test", Kind: ErrTimeout}, nil, 2},
+		{"Unclassified", errors.New("boom"), nil, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &countingDNS{calls: map[string]int{}, err: tt.err}
+			cache, _ := newTestCache(resolver, tt.config)
+
+			for range 2 {
+				if _, err := cache.ResolveAll("a.test"); !errors.Is(err, tt.err) {
+					t.Fatalf("Expected %v, got %v", tt.err, err)
+				}
+			}
+
+			if resolver.calls["a.test"] != tt.calls {
+				t.Errorf("Expected %d resolver calls, got %d", tt.calls, resolver.calls["a.test"])
+			}
+		})
+	}
+}
+
+// TestCachingDNSPartialLookup testa que um Lookup com parte dos tipos falhando
+// é repassado com os registros e não é guardado
+func TestCachingDNSPartialLookup(t *testing.T) {
+	partial := &LookupError{Domain: "a.test", Kind: ErrNoAnswer, TTL: 60}
+	resolver := &countingDNS{calls: map[string]int{}, err: partial}
+	cache, _ := newTestCache(resolver, nil)
+
+	for range 2 {
+		records, err := cache.Lookup("a.test")
+		if records == nil || !errors.Is(err, partial) {
+			t.Fatalf("Expected records with %v, got %+v and %v", partial, records, err)
+		}
+	}
+
+	if resolver.calls["a.test"] != 2 {
+		t.Errorf("Expected 2 resolver calls, got %d", resolver.calls["a.test"])
+	}
+}
+
+// TestCachingDNSEviction testa o descarte LRU e o TTL padrão sem TTL do resolver
+func TestCachingDNSEviction(t *testing.T) {
+	resolver := &countingDNS{calls: map[string]int{}}
+	cache, clock := newTestCache(resolver, &CacheConfig{MaxEntries: 2, DefaultTTL: time.Minute})
+
+	for _, domain := range []string{"a.test", "b.test", "a.test", "c.test", "a.test", "b.test"} {
+		if _, err := cache.ResolveAll(domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// b.test era o menos usado quando c.test entrou
+	expected := map[string]int{"a.test": 1, "b.test": 2, "c.test": 1}
+	for domain, calls := range expected {
+		if resolver.calls[domain] != calls {
+			t.Errorf("Expected %d calls for %s, got %d", calls, domain, resolver.calls[domain])
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 2 || stats.Entries != 2 {
+		t.Errorf("Expected 2 evictions and 2 entries, got %+v", stats)
+	}
+
+	clock.advance(time.Minute)
+	if _, err := cache.ResolveAll("a.test"); err != nil {
+		t.Fatal(err)
+	}
+	if resolver.calls["a.test"] != 2 {
+		t.Errorf("Expected DefaultTTL to expire the entry, got %d calls", resolver.calls["a.test"])
+	}
+
+	cache.Purge()
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Expected empty cache after Purge, got %d entries", stats.Entries)
+	}
+}
+
+// TestCachingDNSBypass testa que Bypass sempre consulta o resolver e atualiza o cache
+func TestCachingDNSBypass(t *testing.T) {
+	resolver := &countingDNS{calls: map[string]int{}}
+	cache, _ := newTestCache(resolver, nil)
+	bypass := cache.Bypass()
+
+	cached, err := cache.ResolveAll("a.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, err := bypass.Resolve("a.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh == cached[0] {
+		t.Errorf("Expected a fresh answer, got cached %s", fresh)
+	}
+	if resolver.calls["a.test"] != 2 {
+		t.Errorf("Expected 2 resolver calls, got %d", resolver.calls["a.test"])
+	}
+
+	// A resposta obtida pelo bypass substitui a guardada
+	ip, err := cache.Resolve("a.test")
+	if err != nil || ip != fresh {
+		t.Errorf("Expected %s from cache, got %s (%v)", fresh, ip, err)
+	}
+
+	if _, err := bypass.Lookup("a.test"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := cache.Stats()
+	if stats.Bypassed != 2 || stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 2 bypassed, 1 hit and 1 miss, got %+v", stats)
+	}
+}
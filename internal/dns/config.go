@@ -0,0 +1,189 @@
+package dns
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout é o prazo de cada tentativa quando Config.Timeout é zero
+	DefaultTimeout = 5 * time.Second
+	// DefaultRetries é o número de novas tentativas quando Config.Retries é zero
+	DefaultRetries = 2
+	// defaultPort é a porta usada quando o servidor não informa uma
+	defaultPort = "53"
+	// defaultDoTPort é a porta padrão de DNS-over-TLS (RFC 7858)
+	defaultDoTPort = "853"
+)
+
+type ResolverType string
+
+const (
+	ResolverTypeSystem ResolverType = "system"
+	ResolverTypeUDP    ResolverType = "udp"
+	ResolverTypeDoH    ResolverType = "doh"
+	ResolverTypeDoT    ResolverType = "dot"
+)
+
+// Config define o tipo, os servidores e os limites de um resolver
+type Config struct {
+	Type ResolverType `json:"type"`
+	// Servers aceita "host" ou "host:porta" para UDP e DoT (portas padrão 53 e
+	// 853) e URLs completas para DoH, como https://dns.example/dns-query
+	Servers []string      `json:"servers"`
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Retries é o número de rodadas extras pelos servidores após a primeira.
+	// Use um valor negativo para não repetir.
+	Retries int `json:"retries,omitempty"`
+	// Method é o método HTTP usado pelo DoH: GET ou POST (padrão)
+	Method string     `json:"method,omitempty"`
+	TLS    *TLSConfig `json:"tls,omitempty"`
+	// Cache, quando informado, envolve o resolver com NewCachingDNS
+	Cache *CacheConfig `json:"cache,omitempty"`
+}
+
+// TLSConfig define a validação TLS usada por DoH e DoT
+type TLSConfig struct {
+	// ServerName sobrescreve o nome usado no SNI e na validação do certificado
+	ServerName string `json:"server_name,omitempty"`
+	// CAFile é um arquivo PEM com as autoridades confiáveis; o padrão é o sistema
+	CAFile             string `json:"ca_file,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	// RootCAs tem precedência sobre CAFile e permite configurar as raízes via código
+	RootCAs *x509.CertPool `json:"-"`
+}
+
+// NewResolver cria o resolver correspondente a config.Type
+func NewResolver(config *Config) (DNS, error) {
+	if config == nil {
+		return NewDNS(), nil
+	}
+
+	var (
+		resolver DNS
+		err      error
+	)
+	switch config.Type {
+	case ResolverTypeSystem, "":
+		resolver = NewDNS()
+	case ResolverTypeUDP:
+		resolver, err = NewWireDNS(config)
+	case ResolverTypeDoH:
+		resolver, err = NewDoHDNS(config)
+	case ResolverTypeDoT:
+		resolver, err = NewDoTDNS(config)
+	default:
+		return nil, ErrUnsupportedResolverType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Cache != nil {
+		resolver = NewCachingDNS(resolver, config.Cache)
+	}
+	return resolver, nil
+}
+
+// rounds retorna timeout e retries com os valores padrão aplicados
+func (c *Config) rounds() (time.Duration, int) {
+	timeout, retries := c.Timeout, c.Retries
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	switch {
+	case retries == 0:
+		retries = DefaultRetries
+	case retries < 0:
+		retries = 0
+	}
+
+	return timeout, retries
+}
+
+// hostPorts normaliza Servers para o formato host:porta
+func (c *Config) hostPorts(port string) ([]string, error) {
+	if c == nil || len(c.Servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	servers := make([]string, 0, len(c.Servers))
+	for _, s := range c.Servers {
+		server, err := normalizeServer(s, port)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, server)
+	}
+
+	return servers, nil
+}
+
+// newWireClient cria o cliente de protocolo DNS a partir da configuração
+func (c *Config) newWireClient() (*wireClient, error) {
+	servers, err := c.hostPorts(defaultPort)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, retries := c.rounds()
+	return &wireClient{
+		servers: servers,
+		timeout: timeout,
+		retries: retries,
+	}, nil
+}
+
+// tlsClientConfig monta a configuração TLS de DoH e DoT
+func (c *Config) tlsClientConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLS == nil {
+		return cfg, nil
+	}
+
+	cfg.ServerName = c.TLS.ServerName
+	cfg.InsecureSkipVerify = c.TLS.InsecureSkipVerify
+
+	switch {
+	case c.TLS.RootCAs != nil:
+		cfg.RootCAs = c.TLS.RootCAs
+	case c.TLS.CAFile != "":
+		pem, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("dns: no certificates found in %s", c.TLS.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
+
+// normalizeServer garante o formato host:porta, aceitando IPv6 sem colchetes
+func normalizeServer(server, port string) (string, error) {
+	server = strings.TrimSpace(server)
+	if server == "" {
+		return "", errors.New("dns: empty server address")
+	}
+
+	if ip := net.ParseIP(strings.Trim(server, "[]")); ip != nil {
+		return net.JoinHostPort(ip.String(), port), nil
+	}
+
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server, nil
+	}
+
+	return net.JoinHostPort(server, port), nil
+}
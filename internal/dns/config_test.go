@@ -0,0 +1,59 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNewResolver testa a seleção do resolver pela configuração (white-box)
+func TestNewResolver(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		check    func(DNS) bool
+		expected error
+	}{
+		{"Nil Config", nil, isType[*dns], nil},
+		{"Empty Type", &Config{}, isType[*dns], nil},
+		{"System", &Config{Type: ResolverTypeSystem}, isType[*dns], nil},
+		{"UDP", &Config{Type: ResolverTypeUDP, Servers: []string{"192.0.2.53"}}, clientType[*wireClient], nil},
+		{"DoH", &Config{Type: ResolverTypeDoH, Servers: []string{"https://dns.example.test/dns-query"}}, clientType[*dohClient], nil},
+		{"DoT", &Config{Type: ResolverTypeDoT, Servers: []string{"dns.example.test"}}, clientType[*dotClient], nil},
+		{"Cached", &Config{Type: ResolverTypeUDP, Servers: []string{"192.0.2.53"}, Cache: &CacheConfig{}}, isType[*cachingDNS], nil},
+		{"UDP Without Servers", &Config{Type: ResolverTypeUDP}, nil, ErrNoServers},
+		{"Unsupported", &Config{Type: "carrier-pigeon"}, nil, ErrUnsupportedResolverType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewResolver(tt.config)
+			if tt.expected != nil {
+				if !errors.Is(err, tt.expected) {
+					t.Errorf("Expected %v, got %v", tt.expected, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !tt.check(resolver) {
+				t.Errorf("Unexpected resolver implementation %T", resolver)
+			}
+		})
+	}
+}
+
+func isType[T DNS](resolver DNS) bool {
+	_, ok := resolver.(T)
+	return ok
+}
+
+func clientType[T exchanger](resolver DNS) bool {
+	w, ok := resolver.(*wireDNS)
+	if !ok {
+		return false
+	}
+	_, ok = w.client.(T)
+	return ok
+}
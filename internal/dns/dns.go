@@ -1,19 +1,78 @@
 package dns
 
-import "net"
+import (
+	"context"
+	"net"
+)
 
-type dns struct{}
+// dns é o resolver do sistema: Resolve usa a resolução da libc/Go e Lookup
+// consulta diretamente os nameservers de resolv.conf, ou resolvers públicos
+// quando o arquivo não existe.
+type dns struct {
+	client exchanger
+}
 
 var _ DNS = (*dns)(nil)
 
 func NewDNS() DNS {
-	return &dns{}
+	return &dns{
+		client: &wireClient{
+			servers: systemServers(resolvConfPath),
+			timeout: DefaultTimeout,
+			retries: DefaultRetries,
+		},
+	}
 }
 
 func (d *dns) Resolve(domain string) (string, error) {
-	ips, err := net.LookupIP(domain)
+	return d.ResolveContext(context.Background(), domain)
+}
+
+func (d *dns) ResolveContext(ctx context.Context, domain string) (string, error) {
+	ips, err := d.ResolveAllContext(ctx, domain)
 	if err != nil {
 		return "", err
 	}
-	return ips[0].String(), nil
+	return ips[0], nil
+}
+
+// ResolveAll retorna todos os endereços IPv4 e IPv6 do domínio
+func (d *dns) ResolveAll(domain string) ([]string, error) {
+	return d.ResolveAllContext(context.Background(), domain)
+}
+
+func (d *dns) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", domain)
+	if err != nil {
+		return nil, lookupError(domain, err)
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+	return addrs, nil
+}
+
+// Lookup consulta todos os tipos de registro suportados para domain
+func (d *dns) Lookup(domain string) (*Records, error) {
+	return d.LookupContext(context.Background(), domain)
+}
+
+func (d *dns) LookupContext(ctx context.Context, domain string) (*Records, error) {
+	return lookupRecords(ctx, d.client, domain)
+}
+
+// LookupType consulta apenas o tipo recordType para domain
+func (d *dns) LookupType(domain string, recordType RecordType) (*Records, error) {
+	return d.LookupTypeContext(context.Background(), domain, recordType)
+}
+
+func (d *dns) LookupTypeContext(ctx context.Context, domain string, recordType RecordType) (*Records, error) {
+	return lookupRecordType(ctx, d.client, domain, recordType)
+}
+
+// lookupRecordType implementa recordTypeResolver, expondo o TTL negativo ao cache
+func (d *dns) lookupRecordType(ctx context.Context, domain string, recordType RecordType) (*Records, uint32, error) {
+	return queryRecordType(ctx, d.client, domain, recordType)
 }
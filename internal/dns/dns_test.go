@@ -1,8 +1,14 @@
 package dns
 
 import (
+	"errors"
 	"net"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // TestNewDNS testa a criação do resolver (white-box)
@@ -12,6 +18,22 @@ func TestNewDNS(t *testing.T) {
 	if resolver == nil {
 		t.Error("NewDNS returned nil")
 	}
+
+	// Acesso interno - servidores do sistema devem ser carregados
+	if d, ok := resolver.(*dns); ok {
+		client, ok := d.client.(*wireClient)
+		if !ok {
+			t.Fatal("Expected system resolver to use a wire client")
+		}
+		if len(client.servers) == 0 {
+			t.Error("Expected system nameservers to be loaded")
+		}
+		if client.timeout != DefaultTimeout {
+			t.Errorf("Expected timeout %v, got %v", DefaultTimeout, client.timeout)
+		}
+	} else {
+		t.Error("NewDNS did not return correct internal type")
+	}
 }
 
 // TestDNSResolve testa a resolução DNS (white-box)
@@ -49,3 +71,238 @@ func TestDNSResolve(t *testing.T) {
 		}
 	})
 }
+
+// exampleZone é a zona usada nos testes de Lookup
+func exampleZone() testZone {
+	return testZone{
+		"www.example.test. A": {
+			rr("www.example.test", 300, &dnsmessage.CNAMEResource{CNAME: mustName("edge.example.test")}),
+			rr("edge.example.test", 60, &dnsmessage.CNAMEResource{CNAME: mustName("lb.cdn.test")}),
+			rr("lb.cdn.test", 30, &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}),
+			rr("lb.cdn.test", 30, &dnsmessage.AResource{A: [4]byte{192, 0, 2, 11}}),
+		},
+		"www.example.test. AAAA": {
+			rr("lb.cdn.test", 30, &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}}),
+		},
+		"www.example.test. MX": {
+			rr("www.example.test", 3600, &dnsmessage.MXResource{Pref: 10, MX: mustName("mx1.example.test")}),
+			rr("www.example.test", 3600, &dnsmessage.MXResource{Pref: 20, MX: mustName("mx2.example.test")}),
+		},
+		"www.example.test. NS": {
+			rr("www.example.test", 86400, &dnsmessage.NSResource{NS: mustName("ns1.example.test")}),
+		},
+		"www.example.test. TXT": {
+			rr("www.example.test", 120, &dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}}),
+		},
+		"www.example.test. SOA": {
+			rr("www.example.test", 900, &dnsmessage.SOAResource{
+				NS: mustName("ns1.example.test"), MBox: mustName("hostmaster.example.test"),
+				Serial: 2024010101, Refresh: 7200, Retry: 900, Expire: 1209600, MinTTL: 300,
+			}),
+		},
+		"www.example.test. CAA": {
+			rr("www.example.test", 600, &dnsmessage.UnknownResource{
+				Type: typeCAA,
+				Data: append([]byte{0, 5}, []byte("issueletsencrypt.org")...),
+			}),
+		},
+	}
+}
+
+// TestDNSLookup testa a consulta de todos os tipos de registro (white-box)
+func TestDNSLookup(t *testing.T) {
+	server := newTestServer(t, exampleZone().handler)
+	resolver := &dns{client: &wireClient{servers: []string{server.addr}, timeout: time.Second}}
+
+	t.Run("All Records", func(t *testing.T) {
+		records, err := resolver.Lookup("www.example.test")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if records.Name != "www.example.test" {
+			t.Errorf("Expected Name www.example.test, got %s", records.Name)
+		}
+
+		if len(records.A) != 2 || records.A[0].IP != "192.0.2.10" || records.A[1].IP != "192.0.2.11" || records.A[0].TTL != 30 {
+			t.Errorf("Unexpected A records: %+v", records.A)
+		}
+
+		if len(records.AAAA) != 1 || records.AAAA[0].IP != "2001:db8::1" {
+			t.Errorf("Unexpected AAAA records: %+v", records.AAAA)
+		}
+
+		expectedChain := []CNAMERecord{
+			{Name: "www.example.test", Target: "edge.example.test", TTL: 300},
+			{Name: "edge.example.test", Target: "lb.cdn.test", TTL: 60},
+		}
+		if len(records.CNAME) != len(expectedChain) {
+			t.Fatalf("Expected CNAME chain %+v, got %+v", expectedChain, records.CNAME)
+		}
+		for i := range expectedChain {
+			if records.CNAME[i] != expectedChain[i] {
+				t.Errorf("CNAME %d: expected %+v, got %+v", i, expectedChain[i], records.CNAME[i])
+			}
+		}
+
+		if len(records.MX) != 2 || records.MX[0].Host != "mx1.example.test" || records.MX[1].Preference != 20 {
+			t.Errorf("Unexpected MX records: %+v", records.MX)
+		}
+
+		if len(records.NS) != 1 || records.NS[0].Host != "ns1.example.test" || records.NS[0].TTL != 86400 {
+			t.Errorf("Unexpected NS records: %+v", records.NS)
+		}
+
+		if len(records.TXT) != 1 || records.TXT[0].Value != "v=spf1 -all" {
+			t.Errorf("Unexpected TXT records: %+v", records.TXT)
+		}
+
+		if records.SOA == nil || records.SOA.Serial != 2024010101 || records.SOA.MBox != "hostmaster.example.test" {
+			t.Errorf("Unexpected SOA record: %+v", records.SOA)
+		}
+
+		if len(records.CAA) != 1 || records.CAA[0].Tag != "issue" || records.CAA[0].Value != "letsencrypt.org" || records.CAA[0].TTL != 600 {
+			t.Errorf("Unexpected CAA records: %+v", records.CAA)
+		}
+
+		ips := records.IPs()
+		if len(ips) != 3 {
+			t.Errorf("Expected 3 IPs, got %v", ips)
+		}
+
+		if server.queryCount() != len(lookupTypes) {
+			t.Errorf("Expected %d queries, got %d", len(lookupTypes), server.queryCount())
+		}
+	})
+
+	t.Run("Single Type", func(t *testing.T) {
+		queries := server.queryCount()
+
+		records, err := resolver.LookupType("www.example.test", RecordTypeCNAME)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		values, ttl, _ := records.Values(RecordTypeCNAME)
+		if len(values) != 2 || values[0] != "edge.example.test" || values[1] != "lb.cdn.test" || ttl != 60 {
+			t.Errorf("Unexpected CNAME values %v with TTL %d", values, ttl)
+		}
+		if len(records.MX) != 0 || records.SOA != nil {
+			t.Errorf("Expected only the queried type, got %+v", records)
+		}
+		if server.queryCount()-queries != 1 {
+			t.Errorf("Expected 1 query, got %d", server.queryCount()-queries)
+		}
+
+		if _, err := resolver.LookupType("www.example.test", "PTR"); !errors.Is(err, ErrUnsupportedRecordType) {
+			t.Errorf("Expected ErrUnsupportedRecordType, got %v", err)
+		}
+	})
+
+	t.Run("Partial Failure", func(t *testing.T) {
+		zone := exampleZone()
+		failing := newTestServer(t, func(q dnsmessage.Question) dnsmessage.Message {
+			if q.Type == dnsmessage.TypeMX || q.Type == typeCAA {
+				return dnsmessage.Message{Header: dnsmessage.Header{RCode: dnsmessage.RCodeServerFailure}}
+			}
+			return zone.handler(q)
+		})
+		resolver := &dns{client: &wireClient{servers: []string{failing.addr}, timeout: time.Second}}
+
+		records, err := resolver.Lookup("www.example.test")
+		if records == nil || len(records.A) != 2 || len(records.NS) != 1 || records.SOA == nil {
+			t.Fatalf("Expected the records of the other types, got %+v", records)
+		}
+		if len(records.MX) != 0 || len(records.CAA) != 0 {
+			t.Errorf("Expected no MX or CAA records, got %+v and %+v", records.MX, records.CAA)
+		}
+
+		var lookupErr *LookupError
+		if !errors.As(err, &lookupErr) || lookupErr.Kind != ErrServFail {
+			t.Fatalf("Expected *LookupError with SERVFAIL, got %v", err)
+		}
+		if msg := err.Error(); !strings.Contains(msg, "MX") || !strings.Contains(msg, "CAA") {
+			t.Errorf("Expected MX and CAA failures in %q", msg)
+		}
+	})
+
+	t.Run("All Types Fail", func(t *testing.T) {
+		failing := newTestServer(t, rcodeHandler(dnsmessage.RCodeRefused))
+		resolver := &dns{client: &wireClient{servers: []string{failing.addr}, timeout: time.Second}}
+
+		records, err := resolver.Lookup("www.example.test")
+		if records != nil || !errors.Is(err, ErrRefused) {
+			t.Errorf("Expected nil records and REFUSED, got %+v and %v", records, err)
+		}
+	})
+
+	t.Run("NXDOMAIN", func(t *testing.T) {
+		_, err := resolver.Lookup("missing.example.test")
+		if err == nil || !strings.Contains(err.Error(), "NXDOMAIN") {
+			t.Errorf("Expected NXDOMAIN error, got %v", err)
+		}
+	})
+
+	t.Run("Empty Domain", func(t *testing.T) {
+		if _, err := resolver.Lookup(""); err == nil {
+			t.Error("Expected error for empty domain")
+		}
+	})
+}
+
+// TestSOAFromAuthority testa o SOA vindo da seção de autoridade (white-box)
+func TestSOAFromAuthority(t *testing.T) {
+	resp := &dnsmessage.Message{
+		Authorities: []dnsmessage.Resource{
+			rr("example.test", 900, &dnsmessage.SOAResource{NS: mustName("ns1.example.test"), MBox: mustName("root.example.test"), Serial: 7}),
+		},
+	}
+
+	records := &Records{Name: "sub.example.test"}
+	collect(records, dnsmessage.TypeSOA, resp)
+
+	if records.SOA == nil || records.SOA.Zone != "example.test" || records.SOA.Serial != 7 {
+		t.Errorf("Expected SOA of parent zone, got %+v", records.SOA)
+	}
+}
+
+// TestSystemServers testa a leitura de resolv.conf (white-box)
+func TestSystemServers(t *testing.T) {
+	path := t.TempDir() + "/resolv.conf"
+	content := "# comment\nsearch example.test\nnameserver 10.0.0.1\nnameserver 2001:db8::53\nnameserver invalid\n"
+	if err := writeFile(path, content); err != nil {
+		t.Fatal(err)
+	}
+
+	servers := systemServers(path)
+	expected := []string{"10.0.0.1:53", "[2001:db8::53]:53"}
+	if len(servers) != len(expected) || servers[0] != expected[0] || servers[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, servers)
+	}
+
+	if got := systemServers(t.TempDir() + "/missing"); !slices.Equal(got, fallbackServers) {
+		t.Errorf("Expected fallback servers for a missing file, got %v", got)
+	}
+
+	empty := t.TempDir() + "/empty.conf"
+	if err := writeFile(empty, "search example.test\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := systemServers(empty); !slices.Equal(got, defaultServers) {
+		t.Errorf("Expected default servers without nameservers, got %v", got)
+	}
+}
+
+// TestParseCAA testa a decodificação de registros CAA (white-box)
+func TestParseCAA(t *testing.T) {
+	if _, ok := parseCAA([]byte{0}); ok {
+		t.Error("Expected short data to be rejected")
+	}
+	if _, ok := parseCAA([]byte{0, 10, 'a'}); ok {
+		t.Error("Expected truncated tag to be rejected")
+	}
+
+	caa, ok := parseCAA(append([]byte{128, 3}, []byte("iodefmailto:sec@example.test")...))
+	if !ok || caa.Flags != 128 || caa.Tag != "iod" {
+		t.Errorf("Unexpected CAA: %+v", caa)
+	}
+}
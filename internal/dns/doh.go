@@ -0,0 +1,155 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dohContentType é o media type das mensagens DNS em DoH (RFC 8484, seção 6)
+const dohContentType = "application/dns-message"
+
+// dohClient envia mensagens DNS em formato wire sobre HTTPS (RFC 8484)
+type dohClient struct {
+	endpoints []string
+	method    string
+	retries   int
+	http      *http.Client
+}
+
+var _ exchanger = (*dohClient)(nil)
+
+// NewDoHDNS cria um resolver DNS-over-HTTPS. Servers deve conter as URLs dos
+// endpoints, como https://cloudflare-dns.com/dns-query.
+func NewDoHDNS(config *Config) (DNS, error) {
+	if config == nil || len(config.Servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	endpoints := make([]string, 0, len(config.Servers))
+	for _, s := range config.Servers {
+		u, err := url.Parse(strings.TrimSpace(s))
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("dns: invalid DoH endpoint %q", s)
+		}
+		endpoints = append(endpoints, u.String())
+	}
+
+	method := strings.ToUpper(config.Method)
+	switch method {
+	case "":
+		method = http.MethodPost
+	case http.MethodGet, http.MethodPost:
+	default:
+		return nil, fmt.Errorf("dns: unsupported DoH method %q", config.Method)
+	}
+
+	tlsConfig, err := config.tlsClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, retries := config.rounds()
+	return &wireDNS{
+		client: &dohClient{
+			endpoints: endpoints,
+			method:    method,
+			retries:   retries,
+			http: &http.Client{
+				Timeout: timeout,
+				Transport: &http.Transport{
+					Proxy:             http.ProxyFromEnvironment,
+					TLSClientConfig:   tlsConfig,
+					ForceAttemptHTTP2: true,
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *dohClient) exchange(ctx context.Context, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	// A RFC 8484 recomenda ID 0 para que as respostas possam ser cacheadas
+	q := *query
+	q.Header.ID = 0
+
+	return exchangeRounds(ctx, c.endpoints, c.retries, &q, c.exchangeEndpoint)
+}
+
+func (c *dohClient) exchangeEndpoint(ctx context.Context, endpoint string, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, endpoint, packed)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", dohContentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dns: DoH endpoint returned %s", resp.Status)
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != dohContentType {
+		return nil, fmt.Errorf("dns: unexpected DoH content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 65536))
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(body); err != nil {
+		return nil, err
+	}
+
+	if err := validateResponse(query, &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// newRequest monta a requisição GET (parâmetro dns em base64url sem padding)
+// ou POST (mensagem no corpo), conforme a RFC 8484, seção 4.1.
+func (c *dohClient) newRequest(ctx context.Context, endpoint string, packed []byte) (*http.Request, error) {
+	switch c.method {
+	case http.MethodGet:
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		params := u.Query()
+		params.Set("dns", base64.RawURLEncoding.EncodeToString(packed))
+		u.RawQuery = params.Encode()
+
+		return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	case http.MethodPost:
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", dohContentType)
+		return req, nil
+	default:
+		return nil, errors.New("dns: unsupported DoH method")
+	}
+}
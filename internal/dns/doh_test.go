@@ -0,0 +1,131 @@
+package dns
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newDoHEndpoint sobe um endpoint DoH local e retorna sua URL e as raízes confiáveis
+func newDoHEndpoint(t *testing.T, methods *[]string) (string, *x509.CertPool) {
+	t.Helper()
+
+	server := newTestServer(t, exampleZone().handler)
+	https := httptest.NewTLSServer(server.dohHandler(methods))
+	t.Cleanup(https.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(https.Certificate())
+	return https.URL + "/dns-query", roots
+}
+
+// TestDoHDNS testa o resolver DNS-over-HTTPS (white-box)
+func TestDoHDNS(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			var methods []string
+			endpoint, roots := newDoHEndpoint(t, &methods)
+
+			resolver, err := NewDoHDNS(&Config{
+				Servers: []string{endpoint},
+				Method:  strings.ToLower(method),
+				Timeout: time.Second,
+				TLS:     &TLSConfig{RootCAs: roots},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			ip, err := resolver.Resolve("www.example.test")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if ip != "192.0.2.10" {
+				t.Errorf("Expected 192.0.2.10, got %s", ip)
+			}
+
+			records, err := resolver.Lookup("www.example.test")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(records.MX) != 2 || len(records.CAA) != 1 {
+				t.Errorf("Expected full record set over DoH, got %+v", records)
+			}
+
+			for _, m := range methods {
+				if m != method {
+					t.Errorf("Expected only %s requests, got %v", method, methods)
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("Untrusted Certificate", func(t *testing.T) {
+		var methods []string
+		endpoint, _ := newDoHEndpoint(t, &methods)
+
+		resolver, _ := NewDoHDNS(&Config{
+			Servers: []string{endpoint},
+			Timeout: time.Second,
+			Retries: -1,
+			TLS:     &TLSConfig{RootCAs: x509.NewCertPool()},
+		})
+
+		if _, err := resolver.Resolve("www.example.test"); err == nil {
+			t.Error("Expected TLS verification error")
+		}
+		if len(methods) != 0 {
+			t.Errorf("Expected no query to reach the server, got %d", len(methods))
+		}
+	})
+
+	t.Run("HTTP Error Status", func(t *testing.T) {
+		https := httptest.NewTLSServer(http.NotFoundHandler())
+		defer https.Close()
+
+		resolver, _ := NewDoHDNS(&Config{
+			Servers: []string{https.URL},
+			Retries: -1,
+			TLS:     &TLSConfig{InsecureSkipVerify: true},
+		})
+
+		_, err := resolver.Resolve("www.example.test")
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("Expected HTTP status error, got %v", err)
+		}
+	})
+}
+
+// TestNewDoHDNSValidation testa a validação da configuração DoH (white-box)
+func TestNewDoHDNSValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{"No Servers", &Config{}},
+		{"Plain HTTP Endpoint", &Config{Servers: []string{"http://dns.example.test/dns-query"}}},
+		{"Bare Host", &Config{Servers: []string{"dns.example.test"}}},
+		{"Unsupported Method", &Config{Servers: []string{"https://dns.example.test/dns-query"}, Method: "PUT"}},
+		{"Missing CA File", &Config{Servers: []string{"https://dns.example.test/dns-query"}, TLS: &TLSConfig{CAFile: "/nonexistent/ca.pem"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDoHDNS(tt.config); err == nil {
+				t.Error("Expected configuration error")
+			}
+		})
+	}
+
+	resolver, err := NewDoHDNS(&Config{Servers: []string{"https://dns.example.test/dns-query"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client := resolver.(*wireDNS).client.(*dohClient); client.method != http.MethodPost {
+		t.Errorf("Expected POST as default method, got %s", client.method)
+	}
+}
@@ -0,0 +1,79 @@
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dotClient envia mensagens DNS sobre TLS (RFC 7858), usando o mesmo
+// enquadramento do DNS sobre TCP
+type dotClient struct {
+	servers []string
+	timeout time.Duration
+	retries int
+	tls     *tls.Config
+}
+
+var _ exchanger = (*dotClient)(nil)
+
+// NewDoTDNS cria um resolver DNS-over-TLS. Servers aceita "host" ou
+// "host:porta"; a porta padrão é 853.
+func NewDoTDNS(config *Config) (DNS, error) {
+	servers, err := config.hostPorts(defaultDoTPort)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := config.tlsClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, retries := config.rounds()
+	return &wireDNS{
+		client: &dotClient{
+			servers: servers,
+			timeout: timeout,
+			retries: retries,
+			tls:     tlsConfig,
+		},
+	}, nil
+}
+
+func (c *dotClient) exchange(ctx context.Context, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	return exchangeRounds(ctx, c.servers, c.retries, query, c.exchangeServer)
+}
+
+func (c *dotClient) exchangeServer(ctx context.Context, server string, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	cfg := c.tls.Clone()
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(server)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ServerName = host
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: c.timeout}, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	stop, err := bindContext(ctx, conn, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	resp, err := exchangeStream(conn, query)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return resp, nil
+}
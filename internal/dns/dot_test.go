@@ -0,0 +1,80 @@
+package dns
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDoTDNS testa o resolver DNS-over-TLS (white-box)
+func TestDoTDNS(t *testing.T) {
+	// Reaproveita o certificado do httptest, válido para example.com e 127.0.0.1
+	https := httptest.NewTLSServer(http.NotFoundHandler())
+	defer https.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(https.Certificate())
+
+	server := newTestServer(t, exampleZone().handler)
+	addr := server.serveDoT(t, https.TLS.Certificates[0])
+
+	t.Run("Lookup", func(t *testing.T) {
+		resolver, err := NewDoTDNS(&Config{
+			Servers: []string{addr},
+			Timeout: time.Second,
+			TLS:     &TLSConfig{RootCAs: roots},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		records, err := resolver.Lookup("www.example.test")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(records.A) != 2 || len(records.CNAME) != 2 || records.SOA == nil {
+			t.Errorf("Expected full record set over DoT, got %+v", records)
+		}
+
+		if _, tcp := server.transportCounts(); tcp != len(lookupTypes) {
+			t.Errorf("Expected %d queries over TLS, got %d", len(lookupTypes), tcp)
+		}
+	})
+
+	t.Run("Server Name Override", func(t *testing.T) {
+		resolver, _ := NewDoTDNS(&Config{
+			Servers: []string{addr},
+			Timeout: time.Second,
+			TLS:     &TLSConfig{RootCAs: roots, ServerName: "example.com"},
+		})
+
+		if _, err := resolver.Resolve("www.example.test"); err != nil {
+			t.Errorf("Expected certificate to match overridden server name, got %v", err)
+		}
+	})
+
+	t.Run("Hostname Mismatch", func(t *testing.T) {
+		resolver, _ := NewDoTDNS(&Config{
+			Servers: []string{addr},
+			Timeout: time.Second,
+			Retries: -1,
+			TLS:     &TLSConfig{RootCAs: roots, ServerName: "dns.other.test"},
+		})
+
+		if _, err := resolver.Resolve("www.example.test"); err == nil {
+			t.Error("Expected certificate verification error")
+		}
+	})
+
+	t.Run("Default Port", func(t *testing.T) {
+		resolver, err := NewDoTDNS(&Config{Servers: []string{"192.0.2.53"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client := resolver.(*wireDNS).client.(*dotClient); client.servers[0] != "192.0.2.53:853" {
+			t.Errorf("Expected default DoT port 853, got %s", client.servers[0])
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNoServers   = errors.New("dns: no servers configured")
+	ErrEmptyDomain = errors.New("dns: empty domain")
+
+	ErrUnsupportedResolverType = errors.New("dns: unsupported resolver type")
+	ErrUnsupportedRecordType   = errors.New("dns: unsupported record type")
+)
+
+// Classificação das falhas de resolução. Os erros retornados pelos resolvers
+// são *LookupError e podem ser comparados com errors.Is.
+var (
+	ErrNXDomain = errors.New("NXDOMAIN")
+	ErrServFail = errors.New("SERVFAIL")
+	ErrRefused  = errors.New("REFUSED")
+	ErrTimeout  = errors.New("timeout")
+	ErrNoAnswer = errors.New("no answer")
+)
+
+// LookupError descreve uma falha de resolução de Domain. Kind é um dos erros
+// de classificação acima, ou nil quando a falha não se encaixa em nenhum deles.
+type LookupError struct {
+	Domain string
+	Kind   error
+	Err    error
+	// TTL é o tempo, em segundos, que a resposta negativa pode ser guardada
+	// segundo o SOA do servidor; 0 quando não informado
+	TTL uint32
+}
+
+func (e *LookupError) Error() string {
+	msg := "dns: lookup " + e.Domain
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Err != nil && e.Err != e.Kind {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *LookupError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	for _, err := range []error{e.Kind, e.Err} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// lookupError classifica err e o envolve em um *LookupError
+func lookupError(domain string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var lookupErr *LookupError
+	if errors.As(err, &lookupErr) {
+		return err
+	}
+
+	return &LookupError{Domain: trimDot(domain), Kind: lookupKind(err), Err: err}
+}
+
+// lookupKind identifica a classificação de erros de transporte e do resolver do sistema
+func lookupKind(err error) error {
+	for _, kind := range []error{ErrNXDomain, ErrServFail, ErrRefused, ErrTimeout, ErrNoAnswer} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsTimeout:
+			return ErrTimeout
+		case dnsErr.IsNotFound:
+			return ErrNXDomain
+		case strings.Contains(dnsErr.Err, "server misbehaving"):
+			return ErrServFail
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package dns
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestWireDNSErrors testa a classificação das falhas de resolução (white-box)
+func TestWireDNSErrors(t *testing.T) {
+	zone := exampleZone()
+	zone["empty.example.test. TXT"] = nil
+
+	tests := []struct {
+		name     string
+		handler  testHandler
+		options  []func(*testServer)
+		domain   string
+		expected error
+	}{
+		{"NXDOMAIN", zone.handler, nil, "missing.example.test", ErrNXDomain},
+		{"SERVFAIL", rcodeHandler(dnsmessage.RCodeServerFailure), nil, "example.test", ErrServFail},
+		{"REFUSED", rcodeHandler(dnsmessage.RCodeRefused), nil, "example.test", ErrRefused},
+		{"No Answer", zone.handler, nil, "empty.example.test", ErrNoAnswer},
+		{"Timeout", zone.handler, []func(*testServer){dropUDP(10)}, "example.test", ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.handler, tt.options...)
+
+			resolver, err := NewWireDNS(&Config{
+				Servers: []string{server.addr},
+				Timeout: 100 * time.Millisecond,
+				Retries: -1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = resolver.ResolveAll(tt.domain)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+
+			var lookupErr *LookupError
+			if !errors.As(err, &lookupErr) {
+				t.Fatalf("Expected *LookupError, got %T", err)
+			}
+			if lookupErr.Domain != tt.domain {
+				t.Errorf("Expected domain %q, got %q", tt.domain, lookupErr.Domain)
+			}
+		})
+	}
+}
+
+// TestLookupKind testa a classificação dos erros do resolver do sistema (white-box)
+func TestLookupKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"Not Found", &net.DNSError{Err: "no such host", IsNotFound: true}, ErrNXDomain},
+		{"Timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, ErrTimeout},
+		{"Server Misbehaving", &net.DNSError{Err: "server misbehaving"}, ErrServFail},
+		{"Deadline", &net.OpError{Op: "read", Err: errTimeout{}}, ErrTimeout},
+		{"Unknown", errors.New("boom"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if kind := lookupKind(tt.err); kind != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, kind)
+			}
+		})
+	}
+}
+
+type errTimeout struct{}
+
+func (errTimeout) Error() string   { return "i/o timeout" }
+func (errTimeout) Timeout() bool   { return true }
+func (errTimeout) Temporary() bool { return true }
@@ -0,0 +1,224 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// exchanger envia uma consulta DNS e retorna a resposta correspondente.
+// É implementado por cada transporte suportado e deve respeitar o
+// cancelamento e o prazo de ctx.
+type exchanger interface {
+	exchange(ctx context.Context, query *dnsmessage.Message) (*dnsmessage.Message, error)
+}
+
+// wireClient fala o protocolo DNS diretamente com uma lista de servidores,
+// via UDP com fallback para TCP quando a resposta vem truncada.
+type wireClient struct {
+	servers []string
+	timeout time.Duration
+	retries int
+}
+
+var _ exchanger = (*wireClient)(nil)
+
+func (c *wireClient) exchange(ctx context.Context, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	return exchangeRounds(ctx, c.servers, c.retries, query, c.exchangeServer)
+}
+
+// exchangeRounds percorre os servidores em ordem, repetindo a rodada até
+// retries vezes. SERVFAIL e REFUSED fazem a consulta seguir para o próximo
+// servidor; se todos falharem assim, a última resposta é retornada. Quando
+// ctx termina, as tentativas restantes são abandonadas e o erro de ctx é
+// retornado.
+func exchangeRounds(
+	ctx context.Context,
+	servers []string,
+	retries int,
+	query *dnsmessage.Message,
+	send func(ctx context.Context, server string, query *dnsmessage.Message) (*dnsmessage.Message, error),
+) (*dnsmessage.Message, error) {
+	if len(servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	var (
+		lastResp *dnsmessage.Message
+		lastErr  error
+	)
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		for _, server := range servers {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			resp, err := send(ctx, server, query)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			switch resp.Header.RCode {
+			case dnsmessage.RCodeServerFailure, dnsmessage.RCodeRefused:
+				lastResp = resp
+				continue
+			}
+
+			return resp, nil
+		}
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+func (c *wireClient) exchangeServer(ctx context.Context, server string, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	resp, err := exchangeUDP(ctx, server, query, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Header.Truncated {
+		return exchangeTCP(ctx, server, query, c.timeout)
+	}
+
+	return resp, nil
+}
+
+// exchangeUDP envia query para server via UDP e aguarda a resposta correspondente.
+// Pacotes que não respondem à consulta são ignorados até o prazo expirar.
+func exchangeUDP(ctx context.Context, server string, query *dnsmessage.Message, timeout time.Duration) (*dnsmessage.Message, error) {
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	stop, err := bindContext(ctx, conn, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	if _, err := conn.Write(packed); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, contextError(ctx, err)
+		}
+
+		var resp dnsmessage.Message
+		if err := resp.Unpack(buf[:n]); err != nil {
+			continue
+		}
+
+		if validateResponse(query, &resp) != nil {
+			continue
+		}
+
+		return &resp, nil
+	}
+}
+
+// exchangeTCP envia query para server via TCP, com o prefixo de tamanho de
+// dois bytes definido na RFC 1035, seção 4.2.2.
+func exchangeTCP(ctx context.Context, server string, query *dnsmessage.Message, timeout time.Duration) (*dnsmessage.Message, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	stop, err := bindContext(ctx, conn, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	resp, err := exchangeStream(conn, query)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return resp, nil
+}
+
+// bindContext limita conn ao menor prazo entre timeout e o de ctx e
+// interrompe as operações pendentes quando ctx é cancelado. A função
+// retornada desfaz a associação e deve ser chamada ao fim da troca.
+func bindContext(ctx context.Context, conn net.Conn, timeout time.Duration) (func() bool, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// Um prazo no passado faz as leituras e escritas pendentes retornarem
+	return context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	}), nil
+}
+
+// contextError substitui o erro de rede pelo de ctx quando a operação foi
+// interrompida pelo cancelamento ou pelo prazo de ctx
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// exchangeStream troca uma mensagem em uma conexão orientada a fluxo (TCP ou TLS)
+func exchangeStream(conn io.ReadWriter, query *dnsmessage.Message) (*dnsmessage.Message, error) {
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	framed := make([]byte, 2, 2+len(packed))
+	binary.BigEndian.PutUint16(framed, uint16(len(packed)))
+	if _, err := conn.Write(append(framed, packed...)); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+
+	var resp dnsmessage.Message
+	if err := resp.Unpack(buf); err != nil {
+		return nil, err
+	}
+
+	if err := validateResponse(query, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
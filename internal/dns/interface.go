@@ -1,5 +1,35 @@
 package dns
 
+import "context"
+
+// DNS resolve nomes. As variantes com Context interrompem a consulta quando
+// ctx é cancelado ou seu prazo expira, retornando um erro que satisfaz
+// errors.Is com o erro de ctx; as demais equivalem a usar context.Background.
 type DNS interface {
 	Resolve(domain string) (string, error)
-}
\ No newline at end of file
+	ResolveAll(domain string) ([]string, error)
+	// Lookup consulta todos os tipos de registro. Se só parte dos tipos
+	// falhar, retorna os registros obtidos junto com um *LookupError
+	Lookup(domain string) (*Records, error)
+	// LookupType consulta apenas os registros do tipo informado
+	LookupType(domain string, recordType RecordType) (*Records, error)
+
+	ResolveContext(ctx context.Context, domain string) (string, error)
+	ResolveAllContext(ctx context.Context, domain string) ([]string, error)
+	LookupContext(ctx context.Context, domain string) (*Records, error)
+	LookupTypeContext(ctx context.Context, domain string, recordType RecordType) (*Records, error)
+}
+
+// CachingDNS é um DNS que guarda as respostas de outro resolver
+type CachingDNS interface {
+	DNS
+	Stats() CacheStats
+	// Bypass retorna um DNS que ignora as respostas guardadas
+	Bypass() DNS
+	Purge()
+}
+
+// PropagationChecker compara as respostas de vários resolvers para um nome
+type PropagationChecker interface {
+	Check(domain string, recordType RecordType, expected ...string) (*PropagationReport, error)
+}
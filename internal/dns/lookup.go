@@ -0,0 +1,160 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// lookupRecords consulta todos os tipos de lookupTypes usando ex. A falha de
+// um tipo não descarta os demais: os registros obtidos são retornados junto
+// com um *LookupError que reúne as falhas de cada tipo. Só retorna registros
+// nil quando todos os tipos falham.
+func lookupRecords(ctx context.Context, ex exchanger, domain string) (*Records, error) {
+	if domain == "" {
+		return nil, ErrEmptyDomain
+	}
+
+	records := &Records{Name: trimDot(domain)}
+	var failures []error
+	for _, qtype := range lookupTypes {
+		resp, err := queryType(ctx, ex, domain, qtype)
+		if err != nil {
+			// Sem o nome ou sem ctx, os demais tipos falhariam da mesma forma
+			if errors.Is(err, ErrNXDomain) || ctx.Err() != nil {
+				return nil, err
+			}
+			failures = append(failures, fmt.Errorf("%s: %w", typeName(qtype), err))
+			continue
+		}
+
+		collect(records, qtype, resp)
+	}
+
+	if len(failures) == 0 {
+		return records, nil
+	}
+
+	err := joinLookupErrors(domain, failures)
+	if len(failures) == len(lookupTypes) {
+		return nil, err
+	}
+	return records, err
+}
+
+// joinLookupErrors reúne as falhas de vários tipos em um único *LookupError.
+// Kind é a classificação comum a todas, ou nil quando diferem, e TTL é o
+// menor TTL negativo entre elas.
+func joinLookupErrors(domain string, failures []error) error {
+	joined := &LookupError{Domain: trimDot(domain), Err: errors.Join(failures...)}
+	for i, failure := range failures {
+		var lookupErr *LookupError
+		if !errors.As(failure, &lookupErr) {
+			lookupErr = &LookupError{}
+		}
+
+		if i == 0 {
+			joined.Kind, joined.TTL = lookupErr.Kind, lookupErr.TTL
+			continue
+		}
+		if joined.Kind != lookupErr.Kind {
+			joined.Kind = nil
+		}
+		joined.TTL = min(joined.TTL, lookupErr.TTL)
+	}
+	return joined
+}
+
+// lookupRecordType consulta apenas o tipo recordType usando ex
+func lookupRecordType(ctx context.Context, ex exchanger, domain string, recordType RecordType) (*Records, error) {
+	records, _, err := queryRecordType(ctx, ex, domain, recordType)
+	return records, err
+}
+
+// queryRecordType é como lookupRecordType, mas também retorna o TTL negativo
+// da resposta (RFC 2308), que vale quando ela não traz registros (NODATA)
+func queryRecordType(ctx context.Context, ex exchanger, domain string, recordType RecordType) (*Records, uint32, error) {
+	if domain == "" {
+		return nil, 0, ErrEmptyDomain
+	}
+
+	qtype, ok := queryTypes[recordType]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w %q", ErrUnsupportedRecordType, recordType)
+	}
+
+	resp, err := queryType(ctx, ex, domain, qtype)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := &Records{Name: trimDot(domain)}
+	collect(records, qtype, resp)
+	return records, negativeTTL(resp), nil
+}
+
+// resolveFirst retorna o primeiro endereço do nome, preferindo IPv4
+func resolveFirst(ctx context.Context, ex exchanger, domain string) (string, error) {
+	ips, err := resolveAll(ctx, ex, domain)
+	if err != nil {
+		return "", err
+	}
+	return ips[0], nil
+}
+
+// resolveAll consulta A e AAAA e retorna os endereços, IPv4 primeiro
+func resolveAll(ctx context.Context, ex exchanger, domain string) ([]string, error) {
+	records, err := resolveAddresses(ctx, ex, domain)
+	if err != nil {
+		return nil, err
+	}
+	return records.IPs(), nil
+}
+
+// resolveAddresses consulta A e AAAA e retorna os registros com seus TTLs.
+// Sem endereços, o erro carrega o TTL negativo informado pelo servidor.
+func resolveAddresses(ctx context.Context, ex exchanger, domain string) (*Records, error) {
+	if domain == "" {
+		return nil, ErrEmptyDomain
+	}
+
+	records := &Records{Name: trimDot(domain)}
+	var ttl uint32
+	for i, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		resp, err := queryType(ctx, ex, domain, qtype)
+		if err != nil {
+			return nil, err
+		}
+		collect(records, qtype, resp)
+
+		if neg := negativeTTL(resp); i == 0 || neg < ttl {
+			ttl = neg
+		}
+	}
+
+	if len(records.IPs()) == 0 {
+		return nil, &LookupError{Domain: trimDot(domain), Kind: ErrNoAnswer, TTL: ttl}
+	}
+	return records, nil
+}
+
+// queryType envia uma consulta e converte RCodes de falha em erro
+func queryType(ctx context.Context, ex exchanger, domain string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	query, err := newQuery(domain, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ex.exchange(ctx, &query)
+	if err != nil {
+		return nil, lookupError(domain, err)
+	}
+
+	if err := rcodeError(domain, qtype, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,272 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// typeCAA não é definido por dnsmessage (RFC 8659)
+	typeCAA dnsmessage.Type = 257
+	// ednsPayloadSize é o tamanho de resposta UDP anunciado via EDNS0
+	ednsPayloadSize = 4096
+)
+
+// lookupTypes são os tipos consultados por Lookup, na ordem de consulta
+var lookupTypes = []dnsmessage.Type{
+	dnsmessage.TypeA,
+	dnsmessage.TypeAAAA,
+	dnsmessage.TypeMX,
+	dnsmessage.TypeNS,
+	dnsmessage.TypeTXT,
+	dnsmessage.TypeSOA,
+	typeCAA,
+}
+
+// queryTypes associa cada RecordType à consulta que o preenche. A cadeia de
+// CNAMEs vem da resposta à consulta A.
+var queryTypes = map[RecordType]dnsmessage.Type{
+	RecordTypeA:     dnsmessage.TypeA,
+	RecordTypeAAAA:  dnsmessage.TypeAAAA,
+	RecordTypeCNAME: dnsmessage.TypeA,
+	RecordTypeMX:    dnsmessage.TypeMX,
+	RecordTypeNS:    dnsmessage.TypeNS,
+	RecordTypeTXT:   dnsmessage.TypeTXT,
+	RecordTypeSOA:   dnsmessage.TypeSOA,
+	RecordTypeCAA:   typeCAA,
+}
+
+// newQuery monta uma consulta recursiva para name/qtype com EDNS0
+func newQuery(name string, qtype dnsmessage.Type) (dnsmessage.Message, error) {
+	qname, err := dnsmessage.NewName(fqdn(name))
+	if err != nil {
+		return dnsmessage.Message{}, err
+	}
+
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(ednsPayloadSize, dnsmessage.RCodeSuccess, false); err != nil {
+		return dnsmessage.Message{}, err
+	}
+
+	return dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               uint16(rand.Uint32()),
+			RecursionDesired: true,
+		},
+		Questions: []dnsmessage.Question{{
+			Name:  qname,
+			Type:  qtype,
+			Class: dnsmessage.ClassINET,
+		}},
+		Additionals: []dnsmessage.Resource{{
+			Header: opt,
+			Body:   &dnsmessage.OPTResource{},
+		}},
+	}, nil
+}
+
+// validateResponse confere se resp responde a query
+func validateResponse(query, resp *dnsmessage.Message) error {
+	if !resp.Header.Response || resp.Header.ID != query.Header.ID {
+		return errors.New("dns: mismatched response")
+	}
+
+	if len(resp.Questions) != 1 || !sameQuestion(resp.Questions[0], query.Questions[0]) {
+		return errors.New("dns: response for a different question")
+	}
+
+	return nil
+}
+
+func sameQuestion(a, b dnsmessage.Question) bool {
+	return a.Type == b.Type && a.Class == b.Class &&
+		strings.EqualFold(a.Name.String(), b.Name.String())
+}
+
+// rcodeError converte o RCode de falha de resp em *LookupError classificado
+func rcodeError(name string, qtype dnsmessage.Type, resp *dnsmessage.Message) error {
+	rcode := resp.Header.RCode
+
+	var kind error
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return nil
+	case dnsmessage.RCodeNameError:
+		kind = ErrNXDomain
+	case dnsmessage.RCodeServerFailure:
+		kind = ErrServFail
+	case dnsmessage.RCodeRefused:
+		kind = ErrRefused
+	}
+
+	return &LookupError{
+		Domain: trimDot(name),
+		Kind:   kind,
+		Err:    fmt.Errorf("%s query returned %s", typeName(qtype), rcodeName(rcode)),
+		TTL:    negativeTTL(resp),
+	}
+}
+
+// negativeTTL retorna por quanto tempo uma resposta negativa pode ser guardada
+// (RFC 2308, seção 5): o menor entre o TTL do SOA da seção de autoridade e o
+// seu campo MINIMUM. Sem SOA, retorna 0.
+func negativeTTL(resp *dnsmessage.Message) uint32 {
+	for _, rr := range resp.Authorities {
+		if soa, ok := rr.Body.(*dnsmessage.SOAResource); ok {
+			return min(rr.Header.TTL, soa.MinTTL)
+		}
+	}
+	return 0
+}
+
+// collect adiciona a records as respostas de uma consulta do tipo qtype
+func collect(records *Records, qtype dnsmessage.Type, resp *dnsmessage.Message) {
+	if qtype == dnsmessage.TypeA {
+		records.CNAME = cnameChain(records.Name, resp.Answers)
+	}
+
+	for _, rr := range resp.Answers {
+		ttl := rr.Header.TTL
+
+		switch body := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			if qtype == dnsmessage.TypeA {
+				records.A = append(records.A, IPRecord{IP: net.IP(body.A[:]).String(), TTL: ttl})
+			}
+		case *dnsmessage.AAAAResource:
+			if qtype == dnsmessage.TypeAAAA {
+				records.AAAA = append(records.AAAA, IPRecord{IP: net.IP(body.AAAA[:]).String(), TTL: ttl})
+			}
+		case *dnsmessage.MXResource:
+			records.MX = append(records.MX, MXRecord{Host: trimDot(body.MX.String()), Preference: body.Pref, TTL: ttl})
+		case *dnsmessage.NSResource:
+			records.NS = append(records.NS, NSRecord{Host: trimDot(body.NS.String()), TTL: ttl})
+		case *dnsmessage.TXTResource:
+			records.TXT = append(records.TXT, TXTRecord{Value: strings.Join(body.TXT, ""), TTL: ttl})
+		case *dnsmessage.SOAResource:
+			records.SOA = soaRecord(rr.Header, body)
+		case *dnsmessage.UnknownResource:
+			if body.Type == typeCAA {
+				if caa, ok := parseCAA(body.Data); ok {
+					caa.TTL = ttl
+					records.CAA = append(records.CAA, caa)
+				}
+			}
+		}
+	}
+
+	// Para nomes abaixo do apex, o SOA da zona vem na seção de autoridade
+	if qtype == dnsmessage.TypeSOA && records.SOA == nil {
+		for _, rr := range resp.Authorities {
+			if body, ok := rr.Body.(*dnsmessage.SOAResource); ok {
+				records.SOA = soaRecord(rr.Header, body)
+				break
+			}
+		}
+	}
+}
+
+// cnameChain segue os CNAMEs de name na seção de resposta, na ordem de resolução
+func cnameChain(name string, answers []dnsmessage.Resource) []CNAMERecord {
+	targets := make(map[string]dnsmessage.Resource)
+	for _, rr := range answers {
+		if rr.Header.Type == dnsmessage.TypeCNAME {
+			targets[strings.ToLower(rr.Header.Name.String())] = rr
+		}
+	}
+
+	var chain []CNAMERecord
+	current := fqdn(name)
+	for len(chain) < len(targets) {
+		rr, ok := targets[strings.ToLower(current)]
+		if !ok {
+			break
+		}
+
+		target := rr.Body.(*dnsmessage.CNAMEResource).CNAME.String()
+		chain = append(chain, CNAMERecord{
+			Name:   trimDot(current),
+			Target: trimDot(target),
+			TTL:    rr.Header.TTL,
+		})
+		current = target
+	}
+
+	return chain
+}
+
+func soaRecord(h dnsmessage.ResourceHeader, body *dnsmessage.SOAResource) *SOARecord {
+	return &SOARecord{
+		Zone:    trimDot(h.Name.String()),
+		NS:      trimDot(body.NS.String()),
+		MBox:    trimDot(body.MBox.String()),
+		Serial:  body.Serial,
+		Refresh: body.Refresh,
+		Retry:   body.Retry,
+		Expire:  body.Expire,
+		MinTTL:  body.MinTTL,
+		TTL:     h.TTL,
+	}
+}
+
+// parseCAA decodifica o RDATA de um registro CAA: flags, tamanho da tag, tag e valor
+func parseCAA(data []byte) (CAARecord, bool) {
+	if len(data) < 2 {
+		return CAARecord{}, false
+	}
+
+	tagLen := int(data[1])
+	if len(data) < 2+tagLen {
+		return CAARecord{}, false
+	}
+
+	return CAARecord{
+		Flags: data[0],
+		Tag:   string(data[2 : 2+tagLen]),
+		Value: string(data[2+tagLen:]),
+	}, true
+}
+
+func typeName(t dnsmessage.Type) string {
+	if t == typeCAA {
+		return "CAA"
+	}
+	return strings.TrimPrefix(t.String(), "Type")
+}
+
+// rcodeName retorna o mnemônico usado pelas ferramentas de DNS (RFC 1035/6895)
+func rcodeName(r dnsmessage.RCode) string {
+	switch r {
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	default:
+		return strings.TrimPrefix(r.String(), "RCode")
+	}
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func trimDot(name string) string {
+	if name == "." {
+		return name
+	}
+	return strings.TrimSuffix(name, ".")
+}
@@ -0,0 +1,218 @@
+package dns
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PropagationStatus string
+
+const (
+	// PropagationStatusPropagated indica que o resolver já serve a resposta esperada
+	PropagationStatusPropagated PropagationStatus = "propagated"
+	// PropagationStatusStale indica que o resolver ainda serve dados antigos
+	PropagationStatusStale PropagationStatus = "stale"
+	// PropagationStatusError indica que a consulta ao resolver falhou
+	PropagationStatusError PropagationStatus = "error"
+)
+
+// PropagationConfig define o conjunto de resolvers consultados, por nome
+type PropagationConfig struct {
+	Resolvers map[string]*Config `json:"resolvers"`
+}
+
+// ResolverAnswer é a resposta de um resolver na verificação de propagação.
+// TTL é o menor TTL servido, ou seja, quanto tempo a resposta ainda pode ficar
+// em cache nesse resolver.
+type ResolverAnswer struct {
+	Resolver string            `json:"resolver"`
+	Values   []string          `json:"values"`
+	TTL      uint32            `json:"ttl"`
+	Status   PropagationStatus `json:"status"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// PropagationReport compara as respostas de todos os resolvers para um nome.
+// Quando nenhum valor esperado é informado, a resposta da maioria é usada
+// como referência e Consensus é true.
+type PropagationReport struct {
+	Domain     string           `json:"domain"`
+	RecordType RecordType       `json:"record_type"`
+	Expected   []string         `json:"expected"`
+	Consensus  bool             `json:"consensus"`
+	Answers    []ResolverAnswer `json:"answers"`
+	Agreeing   []string         `json:"agreeing"`
+	Stale      []string         `json:"stale"`
+	Failed     []string         `json:"failed"`
+	// Propagated é true quando todos os resolvers que responderam concordam
+	Propagated bool `json:"propagated"`
+	// MaxStaleTTL é o maior TTL entre as respostas desatualizadas: o tempo
+	// máximo, em segundos, até que os caches expirem
+	MaxStaleTTL uint32    `json:"max_stale_ttl"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+type propagationChecker struct {
+	resolvers map[string]DNS
+}
+
+var _ PropagationChecker = (*propagationChecker)(nil)
+
+// NewPropagationChecker cria os resolvers descritos em config
+func NewPropagationChecker(config *PropagationConfig) (PropagationChecker, error) {
+	if config == nil || len(config.Resolvers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	resolvers := make(map[string]DNS, len(config.Resolvers))
+	for name, resolverConfig := range config.Resolvers {
+		resolver, err := NewResolver(resolverConfig)
+		if err != nil {
+			return nil, err
+		}
+		resolvers[name] = resolver
+	}
+
+	return NewPropagationCheckerWithResolvers(resolvers), nil
+}
+
+// NewPropagationCheckerWithResolvers usa resolvers já construídos, por nome
+func NewPropagationCheckerWithResolvers(resolvers map[string]DNS) PropagationChecker {
+	return &propagationChecker{resolvers: resolvers}
+}
+
+// Check consulta domain em todos os resolvers concorrentemente e compara os
+// valores do tipo recordType com expected (ou com a resposta da maioria)
+func (p *propagationChecker) Check(domain string, recordType RecordType, expected ...string) (*PropagationReport, error) {
+	if domain == "" {
+		return nil, ErrEmptyDomain
+	}
+
+	// Valida o tipo antes de disparar as consultas
+	if _, _, err := (&Records{}).Values(recordType); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(p.resolvers))
+	for name := range p.resolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := &PropagationReport{
+		Domain:     domain,
+		RecordType: recordType,
+		Answers:    make([]ResolverAnswer, len(names)),
+		CheckedAt:  time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			report.Answers[i] = queryAnswer(p.resolvers[name], name, domain, recordType)
+		}(i, name)
+	}
+	wg.Wait()
+
+	if len(expected) > 0 {
+		report.Expected = normalizeValues(recordType, expected)
+	} else {
+		report.Expected = consensus(report.Answers)
+		report.Consensus = true
+	}
+
+	classify(report)
+	return report, nil
+}
+
+func queryAnswer(resolver DNS, name, domain string, recordType RecordType) ResolverAnswer {
+	answer := ResolverAnswer{Resolver: name}
+
+	records, err := resolver.LookupType(domain, recordType)
+	if err != nil {
+		answer.Status = PropagationStatusError
+		answer.Error = err.Error()
+		return answer
+	}
+
+	answer.Values, answer.TTL, _ = records.Values(recordType)
+	return answer
+}
+
+// consensus retorna a resposta servida pelo maior número de resolvers. Em caso
+// de empate vence a resposta do resolver que vem primeiro na ordem dos nomes.
+func consensus(answers []ResolverAnswer) []string {
+	counts := make(map[string]int)
+	var (
+		best      []string
+		bestCount int
+	)
+
+	for _, answer := range answers {
+		if answer.Status == PropagationStatusError {
+			continue
+		}
+
+		key := answerKey(answer.Values)
+		counts[key]++
+		if counts[key] > bestCount {
+			best, bestCount = answer.Values, counts[key]
+		}
+	}
+
+	return best
+}
+
+// classify marca cada resposta como propagada ou desatualizada e consolida o relatório
+func classify(report *PropagationReport) {
+	expectedKey := answerKey(report.Expected)
+	report.Agreeing, report.Stale, report.Failed = []string{}, []string{}, []string{}
+
+	for i := range report.Answers {
+		answer := &report.Answers[i]
+
+		switch {
+		case answer.Status == PropagationStatusError:
+			report.Failed = append(report.Failed, answer.Resolver)
+		case answerKey(answer.Values) == expectedKey:
+			answer.Status = PropagationStatusPropagated
+			report.Agreeing = append(report.Agreeing, answer.Resolver)
+		default:
+			answer.Status = PropagationStatusStale
+			report.Stale = append(report.Stale, answer.Resolver)
+			if answer.TTL > report.MaxStaleTTL {
+				report.MaxStaleTTL = answer.TTL
+			}
+		}
+	}
+
+	report.Propagated = len(report.Stale) == 0 && len(report.Agreeing) > 0
+}
+
+// normalizeValues coloca os valores esperados na mesma forma de Records.Values
+func normalizeValues(recordType RecordType, values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		switch recordType {
+		case RecordTypeA, RecordTypeAAAA:
+			if ip := net.ParseIP(v); ip != nil {
+				v = ip.String()
+			}
+		case RecordTypeCNAME, RecordTypeMX, RecordTypeNS:
+			v = strings.ToLower(strings.TrimSuffix(v, "."))
+		}
+		normalized = append(normalized, v)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+func answerKey(values []string) string {
+	return strings.Join(values, "\n")
+}
@@ -0,0 +1,213 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// zoneWithA cria uma zona em que www.example.test aponta para ip com o TTL informado
+func zoneWithA(ip [4]byte, ttl uint32) testZone {
+	return testZone{
+		"www.example.test. A": {rr("www.example.test", ttl, &dnsmessage.AResource{A: ip})},
+	}
+}
+
+func wireResolver(t *testing.T, servers ...string) DNS {
+	t.Helper()
+
+	resolver, err := NewWireDNS(&Config{Servers: servers, Timeout: 200 * time.Millisecond, Retries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver
+}
+
+// TestPropagationCheck testa a comparação entre resolvers (white-box)
+func TestPropagationCheck(t *testing.T) {
+	updated := newTestServer(t, zoneWithA([4]byte{192, 0, 2, 20}, 300).handler)
+	updatedToo := newTestServer(t, zoneWithA([4]byte{192, 0, 2, 20}, 280).handler)
+	stale := newTestServer(t, zoneWithA([4]byte{192, 0, 2, 10}, 1200).handler)
+
+	checker := NewPropagationCheckerWithResolvers(map[string]DNS{
+		"alpha":   wireResolver(t, updated.addr),
+		"bravo":   wireResolver(t, stale.addr),
+		"charlie": wireResolver(t, updatedToo.addr),
+		"delta":   wireResolver(t, closedAddr(t)),
+	})
+
+	t.Run("With Expected Value", func(t *testing.T) {
+		report, err := checker.Check("www.example.test", RecordTypeA, "192.0.2.20")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if report.Consensus {
+			t.Error("Expected report based on explicit expected value")
+		}
+		if len(report.Agreeing) != 2 || report.Agreeing[0] != "alpha" || report.Agreeing[1] != "charlie" {
+			t.Errorf("Expected alpha and charlie to agree, got %v", report.Agreeing)
+		}
+		if len(report.Stale) != 1 || report.Stale[0] != "bravo" {
+			t.Errorf("Expected bravo to be stale, got %v", report.Stale)
+		}
+		if len(report.Failed) != 1 || report.Failed[0] != "delta" {
+			t.Errorf("Expected delta to fail, got %v", report.Failed)
+		}
+		if report.Propagated {
+			t.Error("Expected propagation to be incomplete")
+		}
+		if report.MaxStaleTTL != 1200 {
+			t.Errorf("Expected MaxStaleTTL 1200, got %d", report.MaxStaleTTL)
+		}
+
+		// Respostas na ordem dos nomes, com valores e TTL de cada resolver
+		bravo := report.Answers[1]
+		if bravo.Resolver != "bravo" || bravo.Status != PropagationStatusStale || bravo.TTL != 1200 || bravo.Values[0] != "192.0.2.10" {
+			t.Errorf("Unexpected answer for bravo: %+v", bravo)
+		}
+		if report.Answers[3].Error == "" {
+			t.Error("Expected error message for delta")
+		}
+
+		// Cada resolver consulta apenas o tipo verificado
+		if updated.queryCount() != 1 || stale.queryCount() != 1 {
+			t.Errorf("Expected 1 query per resolver, got %d and %d", updated.queryCount(), stale.queryCount())
+		}
+	})
+
+	t.Run("Majority Consensus", func(t *testing.T) {
+		report, err := checker.Check("www.example.test", RecordTypeA)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !report.Consensus {
+			t.Error("Expected consensus-based report")
+		}
+		if len(report.Expected) != 1 || report.Expected[0] != "192.0.2.20" {
+			t.Errorf("Expected majority answer 192.0.2.20, got %v", report.Expected)
+		}
+		if len(report.Stale) != 1 || report.Stale[0] != "bravo" {
+			t.Errorf("Expected bravo to be stale, got %v", report.Stale)
+		}
+	})
+
+	t.Run("Fully Propagated", func(t *testing.T) {
+		report, _ := NewPropagationCheckerWithResolvers(map[string]DNS{
+			"alpha":   wireResolver(t, updated.addr),
+			"charlie": wireResolver(t, updatedToo.addr),
+		}).Check("www.example.test", RecordTypeA, "192.0.2.20")
+
+		if !report.Propagated || report.MaxStaleTTL != 0 {
+			t.Errorf("Expected full propagation, got %+v", report)
+		}
+	})
+
+	t.Run("Invalid Input", func(t *testing.T) {
+		if _, err := checker.Check("", RecordTypeA); !errors.Is(err, ErrEmptyDomain) {
+			t.Errorf("Expected ErrEmptyDomain, got %v", err)
+		}
+		if _, err := checker.Check("www.example.test", "PTR"); err == nil {
+			t.Error("Expected error for unsupported record type")
+		}
+	})
+}
+
+// TestPropagationCheckCNAME testa que o alvo esperado de um CNAME é comparado
+// com o alvo servido por cada resolver (white-box)
+func TestPropagationCheckCNAME(t *testing.T) {
+	zoneWithCNAME := func(target string) testZone {
+		return testZone{
+			"www.example.test. A": {rr("www.example.test", 300, &dnsmessage.CNAMEResource{CNAME: mustName(target)})},
+		}
+	}
+	updated := newTestServer(t, zoneWithCNAME("new-edge.example.test.").handler)
+	stale := newTestServer(t, zoneWithCNAME("old-edge.example.test.").handler)
+
+	report, err := NewPropagationCheckerWithResolvers(map[string]DNS{
+		"alpha": wireResolver(t, updated.addr),
+		"bravo": wireResolver(t, stale.addr),
+	}).Check("www.example.test", RecordTypeCNAME, "New-Edge.example.test.")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(report.Agreeing) != 1 || report.Agreeing[0] != "alpha" {
+		t.Errorf("Expected alpha to agree, got %v (answers %+v)", report.Agreeing, report.Answers)
+	}
+	if len(report.Stale) != 1 || report.Stale[0] != "bravo" {
+		t.Errorf("Expected bravo to be stale, got %v", report.Stale)
+	}
+	if got := report.Answers[1].Values; len(got) != 1 || got[0] != "old-edge.example.test" {
+		t.Errorf("Expected bravo to serve the old target, got %v", got)
+	}
+}
+
+// TestNewPropagationChecker testa a criação a partir da configuração (white-box)
+func TestNewPropagationChecker(t *testing.T) {
+	if _, err := NewPropagationChecker(&PropagationConfig{}); !errors.Is(err, ErrNoServers) {
+		t.Errorf("Expected ErrNoServers, got %v", err)
+	}
+
+	_, err := NewPropagationChecker(&PropagationConfig{Resolvers: map[string]*Config{
+		"broken": {Type: "unknown"},
+	}})
+	if !errors.Is(err, ErrUnsupportedResolverType) {
+		t.Errorf("Expected ErrUnsupportedResolverType, got %v", err)
+	}
+
+	checker, err := NewPropagationChecker(&PropagationConfig{Resolvers: map[string]*Config{
+		"system": {Type: ResolverTypeSystem},
+		"google": {Type: ResolverTypeUDP, Servers: []string{"8.8.8.8"}},
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(checker.(*propagationChecker).resolvers) != 2 {
+		t.Error("Expected two resolvers to be configured")
+	}
+}
+
+// TestRecordsValues testa a extração de valores comparáveis (white-box)
+func TestRecordsValues(t *testing.T) {
+	records := &Records{
+		A:   []IPRecord{{IP: "192.0.2.2", TTL: 60}, {IP: "192.0.2.1", TTL: 30}},
+		MX:  []MXRecord{{Host: "MX.example.test", Preference: 10, TTL: 300}},
+		SOA: &SOARecord{Serial: 42, TTL: 900},
+	}
+
+	values, ttl, err := records.Values(RecordTypeA)
+	if err != nil || len(values) != 2 || values[0] != "192.0.2.1" || ttl != 30 {
+		t.Errorf("Unexpected A values: %v ttl=%d err=%v", values, ttl, err)
+	}
+
+	values, _, _ = records.Values(RecordTypeMX)
+	if len(values) != 1 || values[0] != "10 mx.example.test" {
+		t.Errorf("Unexpected MX values: %v", values)
+	}
+
+	values, _, _ = records.Values(RecordTypeSOA)
+	if len(values) != 1 || values[0] != "42" {
+		t.Errorf("Unexpected SOA values: %v", values)
+	}
+
+	if values, ttl, _ := records.Values(RecordTypeTXT); len(values) != 0 || ttl != 0 {
+		t.Errorf("Expected no TXT values, got %v", values)
+	}
+
+	chain := &Records{CNAME: []CNAMERecord{
+		{Name: "www.example.test", Target: "Edge.example.test", TTL: 300},
+		{Name: "edge.example.test", Target: "lb.cdn.test", TTL: 60},
+	}}
+	values, ttl, _ = chain.Values(RecordTypeCNAME)
+	if len(values) != 2 || values[0] != "edge.example.test" || values[1] != "lb.cdn.test" || ttl != 60 {
+		t.Errorf("Unexpected CNAME values: %v ttl=%d", values, ttl)
+	}
+
+	if got := normalizeValues(RecordTypeMX, []string{"10 MX.example.test."}); got[0] != "10 mx.example.test" {
+		t.Errorf("Unexpected normalized value: %v", got)
+	}
+}
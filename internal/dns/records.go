@@ -0,0 +1,154 @@
+package dns
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Records agrupa todos os registros encontrados para um nome.
+// Os TTLs estão em segundos, como recebidos do servidor.
+type Records struct {
+	Name  string        `json:"name"`
+	A     []IPRecord    `json:"a,omitempty"`
+	AAAA  []IPRecord    `json:"aaaa,omitempty"`
+	CNAME []CNAMERecord `json:"cname,omitempty"`
+	MX    []MXRecord    `json:"mx,omitempty"`
+	NS    []NSRecord    `json:"ns,omitempty"`
+	TXT   []TXTRecord   `json:"txt,omitempty"`
+	SOA   *SOARecord    `json:"soa,omitempty"`
+	CAA   []CAARecord   `json:"caa,omitempty"`
+}
+
+type IPRecord struct {
+	IP  string `json:"ip"`
+	TTL uint32 `json:"ttl"`
+}
+
+// CNAMERecord é um elo da cadeia de CNAMEs, de Name para Target
+type CNAMERecord struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	TTL    uint32 `json:"ttl"`
+}
+
+type MXRecord struct {
+	Host       string `json:"host"`
+	Preference uint16 `json:"preference"`
+	TTL        uint32 `json:"ttl"`
+}
+
+type NSRecord struct {
+	Host string `json:"host"`
+	TTL  uint32 `json:"ttl"`
+}
+
+type TXTRecord struct {
+	Value string `json:"value"`
+	TTL   uint32 `json:"ttl"`
+}
+
+// SOARecord pode pertencer a uma zona acima do nome consultado; Zone indica qual
+type SOARecord struct {
+	Zone    string `json:"zone"`
+	NS      string `json:"ns"`
+	MBox    string `json:"mbox"`
+	Serial  uint32 `json:"serial"`
+	Refresh uint32 `json:"refresh"`
+	Retry   uint32 `json:"retry"`
+	Expire  uint32 `json:"expire"`
+	MinTTL  uint32 `json:"min_ttl"`
+	TTL     uint32 `json:"ttl"`
+}
+
+type CAARecord struct {
+	Flags uint8  `json:"flags"`
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+	TTL   uint32 `json:"ttl"`
+}
+
+// IPs retorna todos os endereços IPv4 e IPv6 encontrados
+func (r *Records) IPs() []string {
+	ips := make([]string, 0, len(r.A)+len(r.AAAA))
+	for _, rec := range r.A {
+		ips = append(ips, rec.IP)
+	}
+	for _, rec := range r.AAAA {
+		ips = append(ips, rec.IP)
+	}
+	return ips
+}
+
+// RecordType identifica um tipo de registro em comparações entre resolvers
+type RecordType string
+
+const (
+	RecordTypeA     RecordType = "A"
+	RecordTypeAAAA  RecordType = "AAAA"
+	RecordTypeCNAME RecordType = "CNAME"
+	RecordTypeMX    RecordType = "MX"
+	RecordTypeNS    RecordType = "NS"
+	RecordTypeTXT   RecordType = "TXT"
+	RecordTypeSOA   RecordType = "SOA"
+	RecordTypeCAA   RecordType = "CAA"
+)
+
+// Values retorna os valores do tipo t em forma textual, ordenados, e o menor
+// TTL entre eles. Para SOA o valor comparado é o serial e, para CNAME, o alvo
+// de cada elo da cadeia.
+func (r *Records) Values(t RecordType) ([]string, uint32, error) {
+	var (
+		values []string
+		ttls   []uint32
+	)
+
+	switch t {
+	case RecordTypeA:
+		for _, rec := range r.A {
+			values, ttls = append(values, rec.IP), append(ttls, rec.TTL)
+		}
+	case RecordTypeAAAA:
+		for _, rec := range r.AAAA {
+			values, ttls = append(values, rec.IP), append(ttls, rec.TTL)
+		}
+	case RecordTypeCNAME:
+		for _, rec := range r.CNAME {
+			values, ttls = append(values, strings.ToLower(rec.Target)), append(ttls, rec.TTL)
+		}
+	case RecordTypeMX:
+		for _, rec := range r.MX {
+			values, ttls = append(values, fmt.Sprintf("%d %s", rec.Preference, strings.ToLower(rec.Host))), append(ttls, rec.TTL)
+		}
+	case RecordTypeNS:
+		for _, rec := range r.NS {
+			values, ttls = append(values, strings.ToLower(rec.Host)), append(ttls, rec.TTL)
+		}
+	case RecordTypeTXT:
+		for _, rec := range r.TXT {
+			values, ttls = append(values, rec.Value), append(ttls, rec.TTL)
+		}
+	case RecordTypeSOA:
+		if r.SOA != nil {
+			values, ttls = append(values, strconv.FormatUint(uint64(r.SOA.Serial), 10)), append(ttls, r.SOA.TTL)
+		}
+	case RecordTypeCAA:
+		for _, rec := range r.CAA {
+			values, ttls = append(values, fmt.Sprintf("%d %s %s", rec.Flags, rec.Tag, rec.Value)), append(ttls, rec.TTL)
+		}
+	default:
+		return nil, 0, fmt.Errorf("%w %q", ErrUnsupportedRecordType, t)
+	}
+
+	sort.Strings(values)
+
+	var minTTL uint32
+	for i, ttl := range ttls {
+		if i == 0 || ttl < minTTL {
+			minTTL = ttl
+		}
+	}
+
+	return values, minTTL, nil
+}
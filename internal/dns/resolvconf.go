@@ -0,0 +1,50 @@
+package dns
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+)
+
+const resolvConfPath = "/etc/resolv.conf"
+
+// defaultServers é usado quando resolv.conf não lista nenhum nameserver,
+// seguindo o mesmo comportamento da libc
+var defaultServers = []string{"127.0.0.1:53", "[::1]:53"}
+
+// fallbackServers é usado quando resolv.conf não existe ou não pode ser lido,
+// como no Windows ou em contêineres mínimos, onde não há um resolver local
+// garantido
+var fallbackServers = []string{"1.1.1.1:53", "8.8.8.8:53", "[2606:4700:4700::1111]:53", "[2001:4860:4860::8888]:53"}
+
+// systemServers retorna os nameservers configurados no sistema no formato host:porta
+func systemServers(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return fallbackServers
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+
+		// Remove zona de endereços IPv6 link-local (fe80::1%eth0)
+		host := fields[1]
+		if ip := net.ParseIP(strings.SplitN(host, "%", 2)[0]); ip == nil {
+			continue
+		}
+
+		servers = append(servers, net.JoinHostPort(host, "53"))
+	}
+
+	if len(servers) == 0 {
+		return defaultServers
+	}
+	return servers
+}
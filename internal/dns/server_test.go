@@ -0,0 +1,307 @@
+package dns
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testHandler produz a resposta do servidor de teste para uma pergunta.
+// Header.ID, Response e Questions são preenchidos pelo servidor.
+type testHandler func(q dnsmessage.Question) dnsmessage.Message
+
+// testServer é um servidor DNS em processo usado nos testes white-box.
+// Atende UDP e TCP no mesmo endereço.
+type testServer struct {
+	addr    string
+	handler testHandler
+
+	mu          sync.Mutex
+	udpQueries  int
+	tcpQueries  int
+	truncateUDP bool
+	dropUDP     int
+}
+
+// newTestServer inicia um servidor DNS em 127.0.0.1 com uma porta livre.
+// options ajustam o comportamento antes de o servidor começar a atender.
+func newTestServer(t *testing.T, handler testHandler, options ...func(*testServer)) *testServer {
+	t.Helper()
+
+	var (
+		tcp net.Listener
+		udp net.PacketConn
+		err error
+	)
+
+	// Tenta algumas portas até conseguir a mesma para UDP e TCP
+	for i := 0; i < 10; i++ {
+		tcp, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to start test DNS server: %v", err)
+		}
+
+		udp, err = net.ListenPacket("udp", tcp.Addr().String())
+		if err == nil {
+			break
+		}
+		tcp.Close()
+	}
+	if err != nil {
+		t.Fatalf("Failed to start test DNS server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		tcp.Close()
+		udp.Close()
+	})
+
+	server := &testServer{addr: tcp.Addr().String(), handler: handler}
+	for _, option := range options {
+		option(server)
+	}
+
+	go server.serveUDP(udp)
+	go server.serveTCP(tcp)
+
+	return server
+}
+
+func (s *testServer) serveUDP(conn net.PacketConn) {
+	buf := make([]byte, 65535)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.udpQueries++
+		drop := s.dropUDP > 0
+		if drop {
+			s.dropUDP--
+		}
+		truncate := s.truncateUDP
+		s.mu.Unlock()
+
+		if drop {
+			continue
+		}
+
+		resp, ok := s.respond(buf[:n], truncate)
+		if !ok {
+			continue
+		}
+		_, _ = conn.WriteTo(resp, peer)
+	}
+}
+
+func (s *testServer) serveTCP(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err != nil {
+				return
+			}
+			buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.tcpQueries++
+			s.mu.Unlock()
+
+			resp, ok := s.respond(buf, false)
+			if !ok {
+				return
+			}
+
+			framed := make([]byte, 2, 2+len(resp))
+			binary.BigEndian.PutUint16(framed, uint16(len(resp)))
+			_, _ = conn.Write(append(framed, resp...))
+		}()
+	}
+}
+
+// respond monta a resposta; truncate simula uma resposta grande demais para UDP
+func (s *testServer) respond(packet []byte, truncate bool) ([]byte, bool) {
+	var query dnsmessage.Message
+	if err := query.Unpack(packet); err != nil || len(query.Questions) != 1 {
+		return nil, false
+	}
+
+	resp := s.handler(query.Questions[0])
+	if truncate {
+		resp = dnsmessage.Message{Header: dnsmessage.Header{Truncated: true}}
+	}
+
+	resp.Header.ID = query.Header.ID
+	resp.Header.Response = true
+	resp.Header.RecursionDesired = query.Header.RecursionDesired
+	resp.Questions = query.Questions
+
+	packed, err := resp.Pack()
+	if err != nil {
+		return nil, false
+	}
+	return packed, true
+}
+
+// queryCount retorna quantas perguntas o servidor recebeu (UDP + TCP)
+func (s *testServer) queryCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.udpQueries + s.tcpQueries
+}
+
+// transportCounts retorna quantas perguntas chegaram por UDP e por TCP
+func (s *testServer) transportCounts() (udp, tcp int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.udpQueries, s.tcpQueries
+}
+
+// truncateUDP faz o servidor responder toda consulta UDP com o bit TC
+func truncateUDP(s *testServer) {
+	s.truncateUDP = true
+}
+
+// dropUDP faz o servidor ignorar as n primeiras consultas UDP
+func dropUDP(n int) func(*testServer) {
+	return func(s *testServer) {
+		s.dropUDP = n
+	}
+}
+
+// testZone é um handler baseado em tabela: "nome. TIPO" -> registros.
+// Nomes ausentes respondem NXDOMAIN; tipos ausentes respondem NOERROR vazio.
+type testZone map[string][]dnsmessage.Resource
+
+func (z testZone) handler(q dnsmessage.Question) dnsmessage.Message {
+	name := strings.ToLower(q.Name.String())
+
+	known := false
+	for key := range z {
+		if strings.HasPrefix(key, name+" ") {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return dnsmessage.Message{Header: dnsmessage.Header{RCode: dnsmessage.RCodeNameError}}
+	}
+
+	return dnsmessage.Message{Answers: z[name+" "+typeName(q.Type)]}
+}
+
+// rcodeHandler responde todas as perguntas com o RCode informado
+func rcodeHandler(rcode dnsmessage.RCode) testHandler {
+	return func(q dnsmessage.Question) dnsmessage.Message {
+		return dnsmessage.Message{Header: dnsmessage.Header{RCode: rcode}}
+	}
+}
+
+// rr monta um registro de recurso para os testes
+func rr(name string, ttl uint32, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  dnsmessage.MustNewName(fqdn(name)),
+			Class: dnsmessage.ClassINET,
+			TTL:   ttl,
+		},
+		Body: body,
+	}
+}
+
+func mustName(name string) dnsmessage.Name {
+	return dnsmessage.MustNewName(fqdn(name))
+}
+
+// closedAddr retorna um endereço UDP sem ninguém escutando
+func closedAddr(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := conn.LocalAddr().String()
+	conn.Close()
+	return addr
+}
+
+func writeFile(path, content string) error {
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// dohHandler expõe o servidor de teste como endpoint DoH, registrando os
+// métodos HTTP recebidos
+func (s *testServer) dohHandler(methods *[]string) http.Handler {
+	var mu sync.Mutex
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			packet []byte
+			err    error
+		)
+
+		switch r.Method {
+		case http.MethodGet:
+			packet, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		case http.MethodPost:
+			if r.Header.Get("Content-Type") != dohContentType {
+				http.Error(w, "bad content type", http.StatusUnsupportedMediaType)
+				return
+			}
+			packet, err = io.ReadAll(r.Body)
+		}
+		if err != nil || len(packet) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		*methods = append(*methods, r.Method)
+		mu.Unlock()
+
+		resp, ok := s.respond(packet, false)
+		if !ok {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", dohContentType)
+		_, _ = w.Write(resp)
+	})
+}
+
+// serveDoT atende conexões DNS-over-TLS com o certificado informado
+func (s *testServer) serveDoT(t *testing.T, cert tls.Certificate) string {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatalf("Failed to start DoT server: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go s.serveTCP(ln)
+	return ln.Addr().String()
+}
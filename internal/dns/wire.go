@@ -0,0 +1,69 @@
+package dns
+
+import "context"
+
+// wireDNS resolve nomes enviando mensagens DNS em formato wire diretamente
+// aos servidores configurados, sem depender do resolver do sistema. O
+// transporte (UDP/TCP, DoH ou DoT) é definido pelo exchanger.
+type wireDNS struct {
+	client exchanger
+}
+
+var _ DNS = (*wireDNS)(nil)
+
+// NewWireDNS cria um resolver que consulta os servidores de config via UDP,
+// com fallback para TCP em respostas truncadas.
+func NewWireDNS(config *Config) (DNS, error) {
+	client, err := config.newWireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &wireDNS{client: client}, nil
+}
+
+// Resolve retorna o primeiro endereço do domínio, preferindo IPv4
+func (w *wireDNS) Resolve(domain string) (string, error) {
+	return w.ResolveContext(context.Background(), domain)
+}
+
+func (w *wireDNS) ResolveContext(ctx context.Context, domain string) (string, error) {
+	return resolveFirst(ctx, w.client, domain)
+}
+
+// ResolveAll retorna todos os endereços IPv4 e IPv6 do domínio
+func (w *wireDNS) ResolveAll(domain string) ([]string, error) {
+	return w.ResolveAllContext(context.Background(), domain)
+}
+
+func (w *wireDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	return resolveAll(ctx, w.client, domain)
+}
+
+// resolveAddresses implementa addressResolver, expondo os TTLs ao cache
+func (w *wireDNS) resolveAddresses(ctx context.Context, domain string) (*Records, error) {
+	return resolveAddresses(ctx, w.client, domain)
+}
+
+// lookupRecordType implementa recordTypeResolver, expondo o TTL negativo ao cache
+func (w *wireDNS) lookupRecordType(ctx context.Context, domain string, recordType RecordType) (*Records, uint32, error) {
+	return queryRecordType(ctx, w.client, domain, recordType)
+}
+
+// Lookup consulta todos os tipos de registro suportados para domain
+func (w *wireDNS) Lookup(domain string) (*Records, error) {
+	return w.LookupContext(context.Background(), domain)
+}
+
+func (w *wireDNS) LookupContext(ctx context.Context, domain string) (*Records, error) {
+	return lookupRecords(ctx, w.client, domain)
+}
+
+// LookupType consulta apenas o tipo recordType para domain
+func (w *wireDNS) LookupType(domain string, recordType RecordType) (*Records, error) {
+	return w.LookupTypeContext(context.Background(), domain, recordType)
+}
+
+func (w *wireDNS) LookupTypeContext(ctx context.Context, domain string, recordType RecordType) (*Records, error) {
+	return lookupRecordType(ctx, w.client, domain, recordType)
+}
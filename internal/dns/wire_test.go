@@ -0,0 +1,235 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestNewWireDNS testa a criação do resolver configurável (white-box)
+func TestNewWireDNS(t *testing.T) {
+	t.Run("No Servers", func(t *testing.T) {
+		if _, err := NewWireDNS(&Config{}); !errors.Is(err, ErrNoServers) {
+			t.Errorf("Expected ErrNoServers, got %v", err)
+		}
+		if _, err := NewWireDNS(nil); !errors.Is(err, ErrNoServers) {
+			t.Errorf("Expected ErrNoServers for nil config, got %v", err)
+		}
+	})
+
+	t.Run("Defaults And Normalization", func(t *testing.T) {
+		resolver, err := NewWireDNS(&Config{Servers: []string{"192.0.2.53", "2001:db8::53", "[2001:db8::54]:5353", "ns.example.test"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		client := resolver.(*wireDNS).client.(*wireClient)
+		expected := []string{"192.0.2.53:53", "[2001:db8::53]:53", "[2001:db8::54]:5353", "ns.example.test:53"}
+		for i, server := range expected {
+			if client.servers[i] != server {
+				t.Errorf("Server %d: expected %s, got %s", i, server, client.servers[i])
+			}
+		}
+
+		if client.timeout != DefaultTimeout || client.retries != DefaultRetries {
+			t.Errorf("Expected default timeout/retries, got %v/%d", client.timeout, client.retries)
+		}
+	})
+
+	t.Run("No Retries", func(t *testing.T) {
+		resolver, _ := NewWireDNS(&Config{Servers: []string{"192.0.2.53"}, Retries: -1, Timeout: time.Second})
+		client := resolver.(*wireDNS).client.(*wireClient)
+		if client.retries != 0 || client.timeout != time.Second {
+			t.Errorf("Expected 0 retries and 1s timeout, got %d/%v", client.retries, client.timeout)
+		}
+	})
+
+	t.Run("Empty Server", func(t *testing.T) {
+		if _, err := NewWireDNS(&Config{Servers: []string{" "}}); err == nil {
+			t.Error("Expected error for empty server address")
+		}
+	})
+}
+
+// TestWireDNSResolve testa Resolve contra o servidor em processo (white-box)
+func TestWireDNSResolve(t *testing.T) {
+	zone := exampleZone()
+	zone["v6only.example.test. AAAA"] = []dnsmessage.Resource{
+		rr("v6only.example.test", 60, &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 2}}),
+	}
+	zone["empty.example.test. TXT"] = nil
+	server := newTestServer(t, zone.handler)
+
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		domain   string
+		expected string
+		errPart  string
+	}{
+		{"IPv4 Through CNAME", "www.example.test", "192.0.2.10", ""},
+		{"IPv6 Fallback", "v6only.example.test", "2001:db8::2", ""},
+		{"No Addresses", "empty.example.test", "", "no answer"},
+		{"NXDOMAIN", "missing.example.test", "", "NXDOMAIN"},
+		{"Empty Domain", "", "", "empty domain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := resolver.Resolve(tt.domain)
+			if tt.errPart != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+					t.Errorf("Expected error containing %q, got %v", tt.errPart, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if ip != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, ip)
+			}
+		})
+	}
+}
+
+// TestWireClientExchange testa transporte, retries e fallback (white-box)
+func TestWireClientExchange(t *testing.T) {
+	query := func(t *testing.T) *dnsmessage.Message {
+		q, err := newQuery("www.example.test", dnsmessage.TypeA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &q
+	}
+
+	t.Run("TCP Fallback On Truncation", func(t *testing.T) {
+		server := newTestServer(t, exampleZone().handler, truncateUDP)
+
+		client := &wireClient{servers: []string{server.addr}, timeout: time.Second}
+		resp, err := client.exchange(context.Background(), query(t))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if resp.Header.Truncated || len(resp.Answers) != 4 {
+			t.Errorf("Expected full answer over TCP, got %d answers (TC=%v)", len(resp.Answers), resp.Header.Truncated)
+		}
+		if udp, tcp := server.transportCounts(); udp != 1 || tcp != 1 {
+			t.Errorf("Expected 1 UDP and 1 TCP query, got %d/%d", udp, tcp)
+		}
+	})
+
+	t.Run("Retries Lost Packets", func(t *testing.T) {
+		server := newTestServer(t, exampleZone().handler, dropUDP(2))
+
+		client := &wireClient{servers: []string{server.addr}, timeout: 100 * time.Millisecond, retries: 2}
+		if _, err := client.exchange(context.Background(), query(t)); err != nil {
+			t.Fatalf("Expected success on third attempt, got %v", err)
+		}
+		if server.queryCount() != 3 {
+			t.Errorf("Expected 3 attempts, got %d", server.queryCount())
+		}
+	})
+
+	t.Run("Gives Up After Retries", func(t *testing.T) {
+		server := newTestServer(t, exampleZone().handler, dropUDP(10))
+
+		client := &wireClient{servers: []string{server.addr}, timeout: 50 * time.Millisecond, retries: 1}
+		if _, err := client.exchange(context.Background(), query(t)); err == nil {
+			t.Fatal("Expected timeout error")
+		}
+		if server.queryCount() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", server.queryCount())
+		}
+	})
+
+	t.Run("Next Server On Failure", func(t *testing.T) {
+		servfail := newTestServer(t, rcodeHandler(dnsmessage.RCodeServerFailure))
+		good := newTestServer(t, exampleZone().handler)
+
+		client := &wireClient{servers: []string{closedAddr(t), servfail.addr, good.addr}, timeout: 200 * time.Millisecond}
+		resp, err := client.exchange(context.Background(), query(t))
+		if err != nil {
+			t.Fatalf("Expected fallback to last server, got %v", err)
+		}
+		if resp.Header.RCode != dnsmessage.RCodeSuccess {
+			t.Errorf("Expected NOERROR, got %v", resp.Header.RCode)
+		}
+		if servfail.queryCount() != 1 || good.queryCount() != 1 {
+			t.Errorf("Expected one query per server, got %d/%d", servfail.queryCount(), good.queryCount())
+		}
+	})
+
+	t.Run("All Servers Fail With RCode", func(t *testing.T) {
+		refused := newTestServer(t, rcodeHandler(dnsmessage.RCodeRefused))
+
+		client := &wireClient{servers: []string{refused.addr}, timeout: 200 * time.Millisecond}
+		resp, err := client.exchange(context.Background(), query(t))
+		if err != nil {
+			t.Fatalf("Expected last response to be returned, got %v", err)
+		}
+		if resp.Header.RCode != dnsmessage.RCodeRefused {
+			t.Errorf("Expected REFUSED, got %v", resp.Header.RCode)
+		}
+	})
+}
+
+// TestWireDNSContext testa que cancelamento e prazo de ctx interrompem a
+// consulta em andamento, sem esperar o timeout e os retries do cliente
+func TestWireDNSContext(t *testing.T) {
+	server := newTestServer(t, exampleZone().handler, dropUDP(100))
+	resolver, err := NewWireDNS(&Config{Servers: []string{server.addr}, Timeout: 5 * time.Second, Retries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := resolver.ResolveAllContext(ctx, "www.example.test")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected lookup to stop on cancel, took %v", elapsed)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := resolver.LookupContext(ctx, "www.example.test")
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrTimeout) {
+			t.Fatalf("Expected deadline classified as timeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected lookup to stop at the deadline, took %v", elapsed)
+		}
+	})
+
+	t.Run("Already Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		queries := server.queryCount()
+		if _, err := resolver.ResolveContext(ctx, "www.example.test"); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if server.queryCount() != queries {
+			t.Errorf("Expected no queries with a canceled context, got %d", server.queryCount()-queries)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package dnschange
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage"
+)
+
+// detectLocks é o número de travas que serializam Detect por domínio
+const detectLocks = 64
+
+type detector struct {
+	storage storage.Storage
+	// locks impede que verificações simultâneas do mesmo domínio registrem a
+	// mesma mudança; cada domínio usa sempre a mesma trava
+	locks [detectLocks]sync.Mutex
+}
+
+var _ Detector = (*detector)(nil)
+
+func NewDetector(storage storage.Storage) Detector {
+	return &detector{
+		storage: storage,
+	}
+}
+
+// Detect compara os endereços resolvidos na verificação com a resposta anterior
+// e com Domain.IP. Um evento é gravado na primeira observação do domínio,
+// quando o conjunto de IPs muda ou quando a conformidade com os IPs esperados
+// muda. Retorna nil quando não há mudança ou quando a verificação não resolveu
+// nenhum endereço. Chamadas simultâneas para o mesmo domínio são serializadas,
+// e só a mudança mais recente é lida do storage.
+func (d *detector) Detect(domain *models.Domain, result *models.CheckResult) (*models.DNSChangeEvent, error) {
+	if domain == nil || result == nil {
+		return nil, ErrInvalidDomain
+	}
+
+	current := resolvedIPs(result)
+	if len(current) == 0 {
+		return nil, nil
+	}
+
+	lock := &d.locks[domain.ID[len(domain.ID)-1]%detectLocks]
+	lock.Lock()
+	defer lock.Unlock()
+
+	last, err := d.storage.LatestDNSChange(domain.ID)
+	if err != nil && !errors.Is(err, storage.ErrDNSChangeNotFound) {
+		return nil, err
+	}
+
+	matches := domain.MatchesExpectedIPs(current)
+
+	var previous []string
+	if last != nil {
+		if slices.Equal(last.CurrentIPs, current) && last.MatchesExpected == matches {
+			return nil, nil
+		}
+		previous = last.CurrentIPs
+	}
+
+	detectedAt := result.CheckedAt
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+
+	event := &models.DNSChangeEvent{
+		ID:              uuid.New(),
+		DomainID:        domain.ID,
+		PreviousIPs:     previous,
+		CurrentIPs:      current,
+		ExpectedIPs:     domain.ExpectedIPs(),
+		MatchesExpected: matches,
+		DetectedAt:      detectedAt,
+	}
+
+	if err := d.storage.SaveDNSChange(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// History retorna as mudanças de IP do domínio em ordem cronológica
+func (d *detector) History(domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	if domainID == uuid.Nil {
+		return nil, ErrInvalidDomain
+	}
+
+	return d.storage.ListDNSChanges(domainID)
+}
+
+// resolvedIPs normaliza e ordena os endereços do resultado para comparação
+func resolvedIPs(result *models.CheckResult) []string {
+	raw := result.ResolvedIPs
+	if len(raw) == 0 && result.ResolvedIP != "" {
+		raw = []string{result.ResolvedIP}
+	}
+
+	seen := make(map[string]bool, len(raw))
+	ips := make([]string, 0, len(raw))
+	for _, addr := range raw {
+		if ip := net.ParseIP(addr); ip != nil && !seen[ip.String()] {
+			seen[ip.String()] = true
+			ips = append(ips, ip.String())
+		}
+	}
+
+	sort.Strings(ips)
+	return ips
+}
@@ -0,0 +1,169 @@
+package dnschange
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+func TestNewDetector(t *testing.T) {
+	if NewDetector(nil) == nil {
+		t.Error("Expected NewDetector to return a non-nil instance")
+	}
+}
+
+// newTrackedDomain cria um domínio no storage de teste
+func newTrackedDomain(t *testing.T, storage *helpers.MockStorage, expectedIP string) *models.Domain {
+	t.Helper()
+
+	domain := &models.Domain{Name: "watched.test", URL: "watched.test", IP: expectedIP}
+	if _, err := storage.CreateDomain(domain); err != nil {
+		t.Fatalf("Failed to create domain: %v", err)
+	}
+	return domain
+}
+
+func resultWith(domain *models.Domain, ips ...string) *models.CheckResult {
+	return &models.CheckResult{
+		ID:          uuid.New(),
+		DomainID:    domain.ID,
+		ResolvedIPs: ips,
+		CheckedAt:   time.Now(),
+	}
+}
+
+// TestDetectHistory testa a sequência de eventos de mudança de IP (white-box)
+func TestDetectHistory(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	detector := NewDetector(storage)
+	domain := newTrackedDomain(t, storage, "")
+
+	// Primeira observação inicia o histórico
+	event, err := detector.Detect(domain, resultWith(domain, "192.0.2.2", "192.0.2.1"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if event == nil || len(event.PreviousIPs) != 0 || len(event.CurrentIPs) != 2 || event.CurrentIPs[0] != "192.0.2.1" {
+		t.Fatalf("Expected initial event with sorted IPs, got %+v", event)
+	}
+	if !event.MatchesExpected {
+		t.Error("Expected match when Domain.IP is empty")
+	}
+
+	// Mesma resposta, em outra ordem: nenhuma mudança
+	event, err = detector.Detect(domain, resultWith(domain, "192.0.2.1", "192.0.2.2"))
+	if err != nil || event != nil {
+		t.Fatalf("Expected no event for unchanged IPs, got %+v (%v)", event, err)
+	}
+
+	// Nova resposta gera evento com os IPs anteriores
+	event, _ = detector.Detect(domain, resultWith(domain, "198.51.100.7"))
+	if event == nil || len(event.PreviousIPs) != 2 || event.CurrentIPs[0] != "198.51.100.7" {
+		t.Fatalf("Expected change event, got %+v", event)
+	}
+
+	history, err := detector.History(domain.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("Expected 2 events in history, got %d", len(history))
+	}
+}
+
+// TestDetectExpectedIPs testa a comparação com Domain.IP (white-box)
+func TestDetectExpectedIPs(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	detector := NewDetector(storage)
+	domain := newTrackedDomain(t, storage, "192.0.2.1, 192.0.2.2")
+
+	event, _ := detector.Detect(domain, resultWith(domain, "192.0.2.1"))
+	if event == nil || !event.MatchesExpected || len(event.ExpectedIPs) != 2 {
+		t.Fatalf("Expected initial event matching expected IPs, got %+v", event)
+	}
+
+	// Endereço fora do conjunto esperado (possível sequestro)
+	event, _ = detector.Detect(domain, resultWith(domain, "192.0.2.1", "203.0.113.66"))
+	if event == nil || event.MatchesExpected {
+		t.Fatalf("Expected mismatch event, got %+v", event)
+	}
+
+	// O usuário passa a esperar o novo endereço: a conformidade muda sem mudança de IP
+	domain.IP = "192.0.2.1,203.0.113.66"
+	event, _ = detector.Detect(domain, resultWith(domain, "192.0.2.1", "203.0.113.66"))
+	if event == nil || !event.MatchesExpected {
+		t.Fatalf("Expected event when expected IPs start matching, got %+v", event)
+	}
+}
+
+// TestDetectWithoutAddresses testa verificações sem resolução (white-box)
+func TestDetectWithoutAddresses(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	detector := NewDetector(storage)
+	domain := newTrackedDomain(t, storage, "")
+
+	event, err := detector.Detect(domain, resultWith(domain))
+	if err != nil || event != nil {
+		t.Errorf("Expected no event without addresses, got %+v (%v)", event, err)
+	}
+
+	// ResolvedIP é usado quando ResolvedIPs não foi preenchido
+	event, _ = detector.Detect(domain, &models.CheckResult{DomainID: domain.ID, ResolvedIP: "192.0.2.9"})
+	if event == nil || event.CurrentIPs[0] != "192.0.2.9" || event.DetectedAt.IsZero() {
+		t.Errorf("Expected event from ResolvedIP, got %+v", event)
+	}
+}
+
+// TestDetectErrors testa entradas inválidas e falhas do storage (white-box)
+func TestDetectErrors(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	detector := NewDetector(storage)
+
+	if _, err := detector.Detect(nil, &models.CheckResult{}); !errors.Is(err, ErrInvalidDomain) {
+		t.Errorf("Expected ErrInvalidDomain, got %v", err)
+	}
+
+	if _, err := detector.History(uuid.Nil); !errors.Is(err, ErrInvalidDomain) {
+		t.Errorf("Expected ErrInvalidDomain, got %v", err)
+	}
+
+	// Domínio inexistente no storage
+	unknown := &models.Domain{ID: uuid.New()}
+	if _, err := detector.Detect(unknown, resultWith(unknown, "192.0.2.1")); err == nil {
+		t.Error("Expected storage error for unknown domain")
+	}
+}
+
+// TestDetectConcurrent testa que verificações simultâneas registram a mesma
+// mudança uma única vez, lendo só o último evento (white-box)
+func TestDetectConcurrent(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := newTrackedDomain(t, storage, "")
+	detector := NewDetector(storage)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := detector.Detect(domain, resultWith(domain, "192.0.2.10")); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if slices.Contains(storage.GetCallHistory(), "ListDNSChanges") {
+		t.Error("Expected Detect not to read the whole history")
+	}
+	history, _ := storage.ListDNSChanges(domain.ID)
+	if len(history) != 1 {
+		t.Errorf("Expected a single change, got %d", len(history))
+	}
+}
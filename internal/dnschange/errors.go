@@ -0,0 +1,7 @@
+package dnschange
+
+import "errors"
+
+var (
+	ErrInvalidDomain = errors.New("invalid domain")
+)
@@ -0,0 +1,11 @@
+package dnschange
+
+import (
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+type Detector interface {
+	Detect(domain *models.Domain, result *models.CheckResult) (*models.DNSChangeEvent, error)
+	History(domainID uuid.UUID) ([]*models.DNSChangeEvent, error)
+}
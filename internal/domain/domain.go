@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,9 @@ import (
 
 type domain struct {
 	storage storage.Storage
+
+	mu        sync.RWMutex
+	listeners []Listener
 }
 
 var _ Domain = (*domain)(nil)
@@ -21,24 +26,42 @@ func NewDomain(storage storage.Storage) Domain {
 }
 
 func (d *domain) Create(domain *models.Domain) (uuid.UUID, error) {
+	return d.CreateContext(context.Background(), domain)
+}
+
+func (d *domain) CreateContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error) {
+	if domain == nil {
+		return uuid.Nil, ErrInvalidDomain
+	}
+
+	if err := normalize(domain); err != nil {
+		return uuid.Nil, err
+	}
+
 	timestamp := time.Now()
 	domain.CreatedAt = timestamp
 	domain.UpdatedAt = timestamp
 
-	id, err := d.storage.CreateDomain(domain)
+	id, err := d.storage.CreateDomainContext(ctx, domain)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	d.notify(EventCreated, id, domain)
+
 	return id, nil
 }
 
 func (d *domain) Get(id uuid.UUID) (*models.Domain, error) {
+	return d.GetContext(context.Background(), id)
+}
+
+func (d *domain) GetContext(ctx context.Context, id uuid.UUID) (*models.Domain, error) {
 	if !isValidUUID(id) {
 		return nil, ErrInvalidUUID
 	}
 
-	domain, err := d.storage.GetDomain(id)
+	domain, err := d.storage.GetDomainContext(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -46,20 +69,56 @@ func (d *domain) Get(id uuid.UUID) (*models.Domain, error) {
 	return domain, nil
 }
 
-func (d *domain) List(page, pageSize int) ([]*models.Domain, error) {
-	if page < 1 || pageSize < 1 {
+func (d *domain) List(opts models.ListOptions) (*models.DomainPage, error) {
+	return d.ListContext(context.Background(), opts)
+}
+
+func (d *domain) ListContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error) {
+	if opts.Page < 1 || opts.PageSize < 1 {
+		return nil, ErrInvalidPagination
+	}
+
+	// Rejeita ordenação e filtro inválidos antes de consultar o storage
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := d.storage.ListDomainsContext(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (d *domain) ListCursor(opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	return d.ListCursorContext(context.Background(), opts)
+}
+
+func (d *domain) ListCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	if opts.Limit < 1 {
 		return nil, ErrInvalidPagination
 	}
 
-	domains, err := d.storage.ListDomains(page, pageSize)
+	// Rejeita ordenação e cursor inválidos antes de consultar o storage
+	if _, _, err := opts.Decode(); err != nil {
+		return nil, err
+	}
+
+	page, err := d.storage.ListDomainsCursorContext(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return domains, nil
+	return page, nil
 }
 
 func (d *domain) Update(domain *models.Domain) error {
+	return d.UpdateContext(context.Background(), domain)
+}
+
+func (d *domain) UpdateContext(ctx context.Context, domain *models.Domain) error {
 	if domain == nil {
 		return ErrInvalidDomain
 	}
@@ -68,27 +127,39 @@ func (d *domain) Update(domain *models.Domain) error {
 		return ErrInvalidUUID
 	}
 
+	if err := normalize(domain); err != nil {
+		return err
+	}
+
 	// Atualiza o timestamp de UpdatedAt
 	domain.UpdatedAt = time.Now()
 
-	err := d.storage.UpdateDomain(domain)
+	err := d.storage.UpdateDomainContext(ctx, domain)
 	if err != nil {
 		return err
 	}
 
+	d.notify(EventUpdated, domain.ID, domain)
+
 	return nil
 }
 
 func (d *domain) Delete(id uuid.UUID) error {
+	return d.DeleteContext(context.Background(), id)
+}
+
+func (d *domain) DeleteContext(ctx context.Context, id uuid.UUID) error {
 	if !isValidUUID(id) {
 		return ErrInvalidUUID
 	}
 
-	err := d.storage.DeleteDomain(id)
+	err := d.storage.DeleteDomainContext(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	d.notify(EventDeleted, id, nil)
+
 	return nil
 }
 
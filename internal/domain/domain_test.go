@@ -1,7 +1,11 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/models"
@@ -129,33 +133,50 @@ func TestListDomains(t *testing.T) {
 	}
 
 	// Testa paginação - primeira página com 2 itens
-	page1, err := domain.List(1, 2)
+	page1, err := domain.List(models.ListOptions{Page: 1, PageSize: 2})
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if len(page1) != 2 {
-		t.Errorf("Expected 2 domains in page 1, got %d", len(page1))
+	if len(page1.Domains) != 2 {
+		t.Errorf("Expected 2 domains in page 1, got %d", len(page1.Domains))
 	}
 
 	// Testa paginação - segunda página com 2 itens
-	page2, err := domain.List(2, 2)
+	page2, err := domain.List(models.ListOptions{Page: 2, PageSize: 2})
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if len(page2) != 2 {
-		t.Errorf("Expected 2 domains in page 2, got %d", len(page2))
+	if len(page2.Domains) != 2 {
+		t.Errorf("Expected 2 domains in page 2, got %d", len(page2.Domains))
 	}
 
 	// Testa paginação - terceira página com 1 item restante
-	page3, err := domain.List(3, 2)
+	page3, err := domain.List(models.ListOptions{Page: 3, PageSize: 2})
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if len(page3) != 1 {
-		t.Errorf("Expected 1 domain in page 3, got %d", len(page3))
+	if len(page3.Domains) != 1 {
+		t.Errorf("Expected 1 domain in page 3, got %d", len(page3.Domains))
+	}
+
+	// As páginas não se sobrepõem e juntas contêm todos os domínios
+	seen := make(map[uuid.UUID]bool)
+	for _, page := range []*models.DomainPage{page1, page2, page3} {
+		if page.Total != len(domains) || page.TotalPages() != 3 {
+			t.Errorf("Expected total %d in 3 pages, got %d in %d", len(domains), page.Total, page.TotalPages())
+		}
+		for _, d := range page.Domains {
+			if seen[d.ID] {
+				t.Errorf("Domain %v returned in more than one page", d.ID)
+			}
+			seen[d.ID] = true
+		}
+	}
+	if len(seen) != len(domains) {
+		t.Errorf("Expected %d distinct domains, got %d", len(domains), len(seen))
 	}
 
 	// Verifica chamadas ao storage (5 Create + 3 ListDomains)
@@ -165,6 +186,143 @@ func TestListDomains(t *testing.T) {
 	}
 }
 
+// TestListDomainsSort testa a ordenação escolhida por quem chama (white-box)
+func TestListDomainsSort(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := NewDomain(storage)
+
+	for _, name := range []string{"bravo", "alpha", "charlie"} {
+		if _, err := domain.Create(&models.Domain{Name: name, URL: name + ".com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := domain.List(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName, Order: models.SortDesc})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var names []string
+	for _, d := range page.Domains {
+		names = append(names, d.Name)
+	}
+	if expected := []string{"charlie", "bravo", "alpha"}; !slices.Equal(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	t.Run("Invalid Sort", func(t *testing.T) {
+		invalid := []models.ListOptions{
+			{Page: 1, PageSize: 10, SortBy: "ip"},
+			{Page: 1, PageSize: 10, Order: "up"},
+		}
+		for _, opts := range invalid {
+			if _, err := domain.List(opts); !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("Expected ErrInvalidSort for %+v, got %v", opts, err)
+			}
+		}
+	})
+}
+
+// TestListDomainsFilter testa que o filtro chega ao storage e que filtros
+// inválidos são rejeitados (white-box)
+func TestListDomainsFilter(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := NewDomain(storage)
+
+	for _, name := range []string{"shop", "blog", "shop-staging"} {
+		if _, err := domain.Create(&models.Domain{Name: name, URL: name + ".com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := domain.List(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName,
+		Filter: models.DomainFilter{Search: "SHOP"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var names []string
+	for _, d := range page.Domains {
+		names = append(names, d.Name)
+	}
+	if expected := []string{"shop", "shop-staging"}; !slices.Equal(names, expected) || page.Total != 2 {
+		t.Errorf("Expected %v with total 2, got %v with total %d", expected, names, page.Total)
+	}
+
+	t.Run("Invalid Filter", func(t *testing.T) {
+		invalid := []models.DomainFilter{
+			{Status: "degraded"},
+			{MinCheckAge: -time.Minute},
+			{MinCheckAge: time.Hour, MaxCheckAge: time.Minute},
+		}
+		for _, filter := range invalid {
+			opts := models.ListOptions{Page: 1, PageSize: 10, Filter: filter}
+			if _, err := domain.List(opts); !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("Expected ErrInvalidFilter for %+v, got %v", filter, err)
+			}
+		}
+	})
+}
+
+// TestListDomainsCursor testa a paginação por cursor (white-box)
+func TestListDomainsCursor(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := NewDomain(storage)
+
+	for range 3 {
+		if _, err := domain.Create(helpers.NewTestDomainBuilder().Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	opts := models.CursorOptions{Limit: 2}
+	for pages := 1; ; pages++ {
+		page, err := domain.ListCursor(opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, d := range page.Domains {
+			seen[d.ID] = true
+		}
+		if page.NextCursor == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	if len(seen) != 3 {
+		t.Errorf("Expected 3 distinct domains, got %d", len(seen))
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		storage.ClearCallHistory()
+
+		tests := []struct {
+			name     string
+			opts     models.CursorOptions
+			expected error
+		}{
+			{"Zero Limit", models.CursorOptions{}, ErrInvalidPagination},
+			{"Invalid Cursor", models.CursorOptions{Cursor: "???", Limit: 2}, ErrInvalidCursor},
+			{"Invalid Sort", models.CursorOptions{Limit: 2, Order: "up"}, ErrInvalidSort},
+		}
+
+		for _, tt := range tests {
+			if _, err := domain.ListCursor(tt.opts); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+
+		if len(storage.GetCallHistory()) != 0 {
+			t.Errorf("Expected 0 calls to storage, got %d", len(storage.GetCallHistory()))
+		}
+	})
+}
+
 // TestListDomainsInvalidPagination testa parâmetros de paginação inválidos (white-box)
 func TestListDomainsInvalidPagination(t *testing.T) {
 	storage := helpers.NewMockStorage()
@@ -183,7 +341,7 @@ func TestListDomainsInvalidPagination(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := domain.List(tt.page, tt.pageSize)
+			_, err := domain.List(models.ListOptions{Page: tt.page, PageSize: tt.pageSize})
 			if err == nil {
 				t.Errorf("Expected error for %s, got nil", tt.name)
 			}
@@ -203,13 +361,13 @@ func TestListDomainsEmptyResult(t *testing.T) {
 
 	// Não cria nenhum domínio, então resultado deve ser vazio
 
-	result, err := domain.List(1, 10)
+	result, err := domain.List(models.ListOptions{Page: 1, PageSize: 10})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	if len(result) != 0 {
-		t.Errorf("Expected empty result, got %d domains", len(result))
+	if len(result.Domains) != 0 || result.Total != 0 {
+		t.Errorf("Expected empty result, got %d domains and total %d", len(result.Domains), result.Total)
 	}
 
 	// Verifica se foi chamado o storage
@@ -224,7 +382,7 @@ func TestListDomainsStorageError(t *testing.T) {
 	storage.SetListDomainsError(true)
 	domain := NewDomain(storage)
 
-	_, err := domain.List(1, 10)
+	_, err := domain.List(models.ListOptions{Page: 1, PageSize: 10})
 	if err == nil {
 		t.Error("Expected error when storage fails, got nil")
 	}
@@ -465,3 +623,100 @@ func TestDeleteDomainStorageError(t *testing.T) {
 		t.Errorf("Expected 1 call to DeleteDomain, got %d", len(storage.GetCallHistory()))
 	}
 }
+
+// TestSubscribe testa a notificação das alterações aos listeners (white-box)
+func TestSubscribe(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := NewDomain(storage)
+
+	var events []Event
+	domain.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	d := &models.Domain{Name: "events.com", URL: "events.com", Interval: 60}
+	id, err := domain.Create(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Interval = 120
+	if err := domain.Update(d); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := domain.Delete(id); err != nil {
+		t.Fatal(err)
+	}
+
+	// Falhas do storage não geram eventos
+	storage.SetDeleteDomainError(true)
+	_ = domain.Delete(id)
+
+	expected := []EventType{EventCreated, EventUpdated, EventDeleted}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+
+	for i, event := range events {
+		if event.Type != expected[i] || event.DomainID != id {
+			t.Errorf("Event %d: expected %s for %v, got %s for %v", i, expected[i], id, event.Type, event.DomainID)
+		}
+	}
+
+	if events[1].Domain == d || events[1].Domain.Interval != 120 {
+		t.Errorf("Expected updated snapshot with interval 120, got %+v", events[1].Domain)
+	}
+	if events[2].Domain != nil {
+		t.Errorf("Expected nil domain on delete, got %+v", events[2].Domain)
+	}
+}
+
+// TestDomainContext testa que o contexto chega ao storage e que operações
+// canceladas não publicam eventos (white-box)
+func TestDomainContext(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	domain := NewDomain(storage)
+
+	var events []Event
+	domain.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	d := &models.Domain{Name: "ctx.com", URL: "ctx.com"}
+	id, err := domain.CreateContext(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := domain.CreateContext(ctx, &models.Domain{Name: "other", URL: "other.com"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on create, got %v", err)
+	}
+	if _, err := domain.GetContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on get, got %v", err)
+	}
+	if _, err := domain.ListContext(ctx, models.ListOptions{Page: 1, PageSize: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on list, got %v", err)
+	}
+	if err := domain.UpdateContext(ctx, d); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on update, got %v", err)
+	}
+	if err := domain.DeleteContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on delete, got %v", err)
+	}
+
+	// Validação continua antes do storage, mesmo com ctx cancelado
+	if _, err := domain.GetContext(ctx, uuid.Nil); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("Expected ErrInvalidUUID, got %v", err)
+	}
+
+	if len(events) != 1 || events[0].Type != EventCreated {
+		t.Errorf("Expected only the create event, got %+v", events)
+	}
+	if _, err := storage.GetDomain(id); err != nil {
+		t.Errorf("Expected domain to survive canceled delete, got %v", err)
+	}
+}
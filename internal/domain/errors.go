@@ -1,9 +1,20 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage"
+)
 
 var (
 	ErrInvalidDomain     = errors.New("invalid domain")
 	ErrInvalidUUID       = errors.New("invalid UUID")
 	ErrInvalidPagination = errors.New("invalid pagination parameters")
+	ErrInvalidSort       = models.ErrInvalidSort
+	ErrInvalidCursor     = models.ErrInvalidCursor
+	ErrInvalidFilter     = models.ErrInvalidFilter
+	// ErrDomainAlreadyExists é retornado quando outro domínio já usa a mesma
+	// URL, comparada depois da normalização
+	ErrDomainAlreadyExists = storage.ErrDomainAlreadyExists
 )
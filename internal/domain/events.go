@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// EventType identifica a alteração feita em um domínio
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event descreve uma alteração gravada no storage. Domain é uma cópia do
+// domínio gravado e é nil em EventDeleted.
+type Event struct {
+	Type     EventType
+	DomainID uuid.UUID
+	Domain   *models.Domain
+}
+
+// Listener recebe os eventos de forma síncrona, após a gravação no storage
+type Listener func(event Event)
+
+// Subscribe registra listener para as alterações feitas através de Domain
+func (d *domain) Subscribe(listener Listener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.listeners = append(d.listeners, listener)
+}
+
+// notify entrega o evento a todos os listeners registrados
+func (d *domain) notify(eventType EventType, id uuid.UUID, domain *models.Domain) {
+	d.mu.RLock()
+	listeners := d.listeners
+	d.mu.RUnlock()
+
+	event := Event{Type: eventType, DomainID: id}
+	for _, listener := range listeners {
+		if domain != nil {
+			snapshot := *domain
+			event.Domain = &snapshot
+		}
+		listener(event)
+	}
+}
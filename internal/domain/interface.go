@@ -1,14 +1,36 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/models"
 )
 
 type Domain interface {
+	// Create e Update validam e normalizam Name e URL no próprio domain antes
+	// de gravar. Campos inválidos resultam em um *ValidationError, e uma URL
+	// já usada por outro domínio em ErrDomainAlreadyExists.
 	Create(domain *models.Domain) (uuid.UUID, error)
 	Get(id uuid.UUID) (*models.Domain, error)
-	List(page, pageSize int) ([]*models.Domain, error)
+	// List retorna uma página de domínios e o total, ordenada por opts.SortBy
+	// e opts.Order; por padrão, em ordem de criação
+	List(opts models.ListOptions) (*models.DomainPage, error)
+	// ListCursor pagina na mesma ordem de List, continuando de opts.Cursor.
+	// Domínios criados entre as chamadas não deslocam as páginas seguintes.
+	ListCursor(opts models.CursorOptions) (*models.DomainCursorPage, error)
 	Update(domain *models.Domain) error
 	Delete(id uuid.UUID) error
+
+	// Variantes com Context dos métodos acima, repassado ao storage. Quando
+	// ctx termina antes da gravação, nenhum evento é publicado.
+	CreateContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error)
+	GetContext(ctx context.Context, id uuid.UUID) (*models.Domain, error)
+	ListContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error)
+	ListCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error)
+	UpdateContext(ctx context.Context, domain *models.Domain) error
+	DeleteContext(ctx context.Context, id uuid.UUID) error
+
+	// Subscribe registra listener para as alterações feitas através de Domain
+	Subscribe(listener Listener)
 }
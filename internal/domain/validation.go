@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"errors"
+	"maps"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/luizhreis/domain-watcher/internal/jsonpath"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/idna"
+)
+
+const (
+	// MaxNameLength é o tamanho máximo de Domain.Name, em caracteres
+	MaxNameLength = 255
+	// MaxTimeout é o maior Domain.Timeout aceito, em segundos. Zero usa o
+	// padrão do checker.
+	MaxTimeout = 300
+
+	// maxHostnameLength e maxLabelLength são os limites da RFC 1123
+	maxHostnameLength = 253
+	maxLabelLength    = 63
+)
+
+// FieldError descreve um campo inválido do domínio
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lista todos os campos inválidos de um domínio. Para
+// errors.Is, equivale a ErrInvalidDomain.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return "invalid domain: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidDomain
+}
+
+// add registra um campo inválido
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// normalize valida os campos editáveis do domínio e, se todos forem
+// válidos, grava neles a forma normalizada. Em caso de erro, o domínio não
+// é alterado e o erro é um *ValidationError com todos os campos inválidos.
+func normalize(domain *models.Domain) error {
+	verr := &ValidationError{}
+
+	name := strings.TrimSpace(domain.Name)
+	switch {
+	case name == "":
+		verr.add("name", "is required")
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		verr.add("name", "must be at most "+strconv.Itoa(MaxNameLength)+" characters")
+	}
+
+	rawURL, err := normalizeURL(domain.URL)
+	if err != nil {
+		verr.add("url", err.Error())
+	}
+
+	if domain.Timeout < 0 || domain.Timeout > MaxTimeout {
+		verr.add("timeout", "must be between 0 and "+strconv.Itoa(MaxTimeout)+" seconds")
+	}
+	if domain.Interval < 0 {
+		verr.add("interval", "must not be negative")
+	}
+	validateAssertions(domain.Assertions, verr)
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+
+	domain.Name = name
+	domain.URL = rawURL
+	return nil
+}
+
+// validateAssertions registra em verr as asserções inválidas, identificadas
+// como assertions.<campo>
+func validateAssertions(a *models.Assertions, verr *ValidationError) {
+	if a == nil {
+		return
+	}
+
+	for _, code := range a.StatusCodes {
+		if code < 100 || code > 599 {
+			verr.add("assertions.status_codes", "must be between 100 and 599")
+			break
+		}
+	}
+
+	if slices.Contains(a.BodyContains, "") {
+		verr.add("assertions.body_contains", "must not contain empty strings")
+	}
+	if slices.Contains(a.BodyNotContains, "") {
+		verr.add("assertions.body_not_contains", "must not contain empty strings")
+	}
+	if expr, ok := invalidRegexp(a.BodyMatches); ok {
+		verr.add("assertions.body_matches", "invalid regular expression "+strconv.Quote(expr))
+	}
+	if expr, ok := invalidRegexp(a.BodyNotMatches); ok {
+		verr.add("assertions.body_not_matches", "invalid regular expression "+strconv.Quote(expr))
+	}
+
+	for _, expr := range a.JSON {
+		if _, err := jsonpath.Parse(expr); err != nil {
+			verr.add("assertions.json", err.Error())
+			break
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(a.Headers)) {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(a.Headers[name]) {
+			verr.add("assertions.headers", "invalid header "+strconv.Quote(name))
+			break
+		}
+	}
+
+	if a.MaxBodySize < 0 {
+		verr.add("assertions.max_body_size", "must not be negative")
+	}
+	if a.MaxLatency < 0 {
+		verr.add("assertions.max_latency_ms", "must not be negative")
+	}
+}
+
+// invalidRegexp retorna a primeira expressão de exprs que não compila
+func invalidRegexp(exprs []string) (string, bool) {
+	for _, expr := range exprs {
+		if _, err := regexp.Compile(expr); err != nil {
+			return expr, true
+		}
+	}
+	return "", false
+}
+
+// normalizeURL converte a URL do domínio para a forma canônica: esquema
+// http ou https (https quando omitido), host em minúsculas e em punycode,
+// sem a porta padrão do esquema, sem barra final isolada e sem fragmento.
+func normalizeURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", errors.New("is required")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("is not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.New("scheme must be http or https")
+	}
+	if u.User != nil {
+		return "", errors.New("must not contain credentials")
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+
+	port := u.Port()
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", errors.New("port must be between 1 and 65535")
+		}
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			port = ""
+		}
+	}
+
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "/" && u.RawQuery == "" {
+		u.Path = ""
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// normalizeHost aceita endereços IP e nomes válidos pela RFC 1123, depois
+// da conversão de nomes internacionalizados para punycode
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", errors.New("must include a host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(host, "."))
+	if err != nil || !isValidHostname(ascii) {
+		return "", errors.New("host is not a valid domain name")
+	}
+	return ascii, nil
+}
+
+// isValidHostname verifica a sintaxe de nomes da RFC 1123: rótulos de 1 a 63
+// letras, dígitos e hífens, sem hífen nas pontas, e o último rótulo não
+// inteiramente numérico, para não ser confundido com um IPv4
+func isValidHostname(host string) bool {
+	if host == "" || len(host) > maxHostnameLength {
+		return false
+	}
+
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > maxLabelLength {
+			return false
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	last := labels[len(labels)-1]
+	return strings.Trim(last, "0123456789") != ""
+}
@@ -0,0 +1,7 @@
+package jsonpath
+
+import "errors"
+
+var (
+	ErrInvalidExpression = errors.New("invalid expression")
+)
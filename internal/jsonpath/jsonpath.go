@@ -0,0 +1,235 @@
+// Package jsonpath avalia expressões no estilo JSONPath sobre documentos JSON,
+// como $.status == "ok" ou $.db.latency_ms < 200.
+//
+// Uma expressão é um caminho seguido, opcionalmente, de um operador (==, !=,
+// <, <=, > ou >=) e de um literal JSON. O caminho começa em $ e acessa campos
+// com .nome ou ["nome"] e posições de arrays com [0]. Sem operador, a
+// expressão exige apenas que o caminho exista. Curingas, filtros e buscas
+// recursivas não são suportados.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// operators em ordem de tentativa: os de dois caracteres antes dos de um
+var operators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// Expression é uma expressão já interpretada por Parse
+type Expression struct {
+	raw   string
+	path  []segment
+	op    string
+	value any
+}
+
+// segment acessa um campo de objeto (key) ou uma posição de array (index)
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Parse interpreta expr. Erros envolvem ErrInvalidExpression.
+func Parse(expr string) (*Expression, error) {
+	e := &Expression{raw: strings.TrimSpace(expr)}
+
+	rest, err := e.parsePath(e.raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidExpression, expr, err)
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return e, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			e.op = op
+			break
+		}
+	}
+	if e.op == "" {
+		return nil, fmt.Errorf("%w %q: unexpected %q", ErrInvalidExpression, expr, rest)
+	}
+
+	literal := strings.TrimSpace(rest[len(e.op):])
+	if err := json.Unmarshal([]byte(literal), &e.value); err != nil {
+		return nil, fmt.Errorf("%w %q: value %q is not a JSON literal", ErrInvalidExpression, expr, literal)
+	}
+
+	return e, nil
+}
+
+// parsePath consome o caminho no início de s e retorna o restante
+func (e *Expression) parsePath(s string) (string, error) {
+	if !strings.HasPrefix(s, "$") {
+		return "", fmt.Errorf("path must start with $")
+	}
+	s = s[1:]
+
+	for {
+		switch {
+		case strings.HasPrefix(s, "."):
+			n := strings.IndexFunc(s[1:], func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+			})
+			if n < 0 {
+				n = len(s) - 1
+			}
+			if n == 0 {
+				return "", fmt.Errorf("missing field name after .")
+			}
+			e.path = append(e.path, segment{key: s[1 : n+1]})
+			s = s[n+1:]
+
+		case strings.HasPrefix(s, "["):
+			end := closingBracket(s)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated [")
+			}
+			seg, err := parseBracket(s[1:end])
+			if err != nil {
+				return "", err
+			}
+			e.path = append(e.path, seg)
+			s = s[end+1:]
+
+		default:
+			return s, nil
+		}
+	}
+}
+
+// closingBracket retorna a posição do ] que fecha o [ inicial de s,
+// ignorando os que estão dentro de aspas
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseBracket interpreta o conteúdo de [...]: um índice ou um nome entre aspas
+func parseBracket(s string) (segment, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '\'' {
+			s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+		}
+		key, err := strconv.Unquote(s)
+		if err != nil {
+			return segment{}, fmt.Errorf("invalid field name %s", s)
+		}
+		return segment{key: key}, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return segment{}, fmt.Errorf("invalid index [%s]", s)
+	}
+	return segment{index: index, isIndex: true}, nil
+}
+
+// String retorna a expressão como foi informada
+func (e *Expression) String() string {
+	return e.raw
+}
+
+// Evaluate avalia a expressão sobre doc, decodificado por encoding/json. Retorna
+// se ela é satisfeita, o valor encontrado no caminho e se o caminho existe;
+// um caminho inexistente nunca satisfaz a expressão.
+func (e *Expression) Evaluate(doc any) (ok bool, actual any, found bool) {
+	actual, found = lookup(doc, e.path)
+	if !found {
+		return false, nil, false
+	}
+	if e.op == "" {
+		return true, actual, true
+	}
+	return compare(actual, e.op, e.value), actual, true
+}
+
+func lookup(doc any, path []segment) (any, bool) {
+	current := doc
+	for _, seg := range path {
+		if seg.isIndex {
+			array, ok := current.([]any)
+			if !ok || seg.index >= len(array) {
+				return nil, false
+			}
+			current = array[seg.index]
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[seg.key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// compare aplica op. Igualdade compara quaisquer valores JSON; as relações de
+// ordem valem apenas entre dois números ou duas strings.
+func compare(actual any, op string, expected any) bool {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(actual, expected)
+	case "!=":
+		return !reflect.DeepEqual(actual, expected)
+	}
+
+	var cmp int
+	switch a := actual.(type) {
+	case float64:
+		b, ok := expected.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	case string:
+		b, ok := expected.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(a, b)
+	default:
+		return false
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
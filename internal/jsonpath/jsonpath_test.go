@@ -0,0 +1,119 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const document = `{
+	"status": "ok",
+	"version": "1.4.2",
+	"db": {"latency_ms": 120, "primary": true, "replicas": null},
+	"queues": [{"name": "mail", "depth": 3}, {"name": "jobs", "depth": 250}],
+	"content-type": "json",
+	"weird key": {"a.b": 1}
+}`
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		"$",
+		"$.status",
+		`$.status == "ok"`,
+		`$.status=="ok"`,
+		"$.db.latency_ms < 200",
+		"$.queues[1].depth >= 100",
+		`$["weird key"]['a.b'] == 1`,
+		"$.db.replicas == null",
+		`$.queues == [{"name": "mail"}]`,
+	}
+	for _, expr := range valid {
+		if _, err := Parse(expr); err != nil {
+			t.Errorf("Expected %q to parse, got %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"status == 1",
+		"$.",
+		"$..status",
+		"$.queues[-1]",
+		"$.queues[x]",
+		`$["unterminated`,
+		"$.status = 1",
+		"$.status ==",
+		"$.status == ok",
+		`$.status == "ok" extra`,
+	}
+	for _, expr := range invalid {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Expected ErrInvalidExpression for %q, got %v", expr, err)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr   string
+		ok     bool
+		actual any
+		found  bool
+	}{
+		{`$.status == "ok"`, true, "ok", true},
+		{`$.status != "ok"`, false, "ok", true},
+		{"$.db.latency_ms < 200", true, 120.0, true},
+		{"$.db.latency_ms < 100", false, 120.0, true},
+		{"$.db.latency_ms <= 120", true, 120.0, true},
+		{"$.db.primary == true", true, true, true},
+		{"$.db.replicas == null", true, nil, true},
+		{"$.db.replicas", true, nil, true},
+		{"$.queues[1].depth > 100", true, 250.0, true},
+		{`$.queues[0].name == "mail"`, true, "mail", true},
+		{`$.version >= "1.4"`, true, "1.4.2", true},
+		{`$.content-type == "json"`, true, "json", true},
+		{`$["weird key"]["a.b"] == 1`, true, 1.0, true},
+		{`$.db == {"latency_ms": 120, "primary": true, "replicas": null}`, true, doc.(map[string]any)["db"], true},
+
+		// Relações de ordem entre tipos diferentes não são satisfeitas
+		{`$.status < 5`, false, "ok", true},
+		{`$.db.primary > false`, false, true, true},
+
+		// Caminhos inexistentes nunca satisfazem a expressão
+		{"$.missing", false, nil, false},
+		{"$.missing != 1", false, nil, false},
+		{"$.queues[5].depth < 10", false, nil, false},
+		{"$.status.nested", false, nil, false},
+		{"$.db[0]", false, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			ok, actual, found := e.Evaluate(doc)
+			if ok != tt.ok || found != tt.found || !reflect.DeepEqual(actual, tt.actual) {
+				t.Errorf("Expected (%v, %v, %v), got (%v, %v, %v)", tt.ok, tt.actual, tt.found, ok, actual, found)
+			}
+		})
+	}
+}
+
+func TestExpressionString(t *testing.T) {
+	e, err := Parse(`  $.status == "ok"  `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.String() != `$.status == "ok"` {
+		t.Errorf("Unexpected String() %q", e.String())
+	}
+}
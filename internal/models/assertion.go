@@ -0,0 +1,62 @@
+package models
+
+// Assertions define o que a resposta final do domínio precisa satisfazer
+// para que a verificação seja considerada bem-sucedida. Sem StatusCodes, o
+// status HTTP também precisa ser 2xx ou 3xx. Campos vazios não são verificados.
+type Assertions struct {
+	// StatusCodes lista os status HTTP aceitos. Quando informado, substitui a
+	// regra 2xx/3xx: um status da lista, como 401, mantém o domínio no ar.
+	StatusCodes []int `json:"status_codes,omitempty"`
+
+	// BodyContains e BodyNotContains são trechos que o corpo deve ou não conter
+	BodyContains    []string `json:"body_contains,omitempty"`
+	BodyNotContains []string `json:"body_not_contains,omitempty"`
+
+	// BodyMatches e BodyNotMatches são expressões regulares (sintaxe RE2)
+	// que o corpo deve ou não satisfazer
+	BodyMatches    []string `json:"body_matches,omitempty"`
+	BodyNotMatches []string `json:"body_not_matches,omitempty"`
+
+	// JSON são expressões no estilo JSONPath avaliadas sobre o corpo
+	// interpretado como JSON, como $.status == "ok" (veja o pacote jsonpath)
+	JSON []string `json:"json,omitempty"`
+
+	// Headers lista os cabeçalhos obrigatórios. Com valor vazio, basta o
+	// cabeçalho estar presente; caso contrário, algum valor deve ser igual.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// MaxBodySize é o tamanho máximo do corpo, em bytes
+	MaxBodySize int64 `json:"max_body_size,omitempty"`
+
+	// MaxLatency é o maior ResponseTime aceito, em milissegundos
+	MaxLatency int64 `json:"max_latency_ms,omitempty"`
+}
+
+// HasBodyChecks informa se alguma asserção depende do conteúdo do corpo
+func (a *Assertions) HasBodyChecks() bool {
+	return a != nil && (len(a.BodyContains) > 0 || len(a.BodyNotContains) > 0 ||
+		len(a.BodyMatches) > 0 || len(a.BodyNotMatches) > 0 || len(a.JSON) > 0)
+}
+
+// Nomes das asserções registrados em AssertionFailure.Assertion, iguais aos
+// campos JSON de Assertions
+const (
+	AssertionStatusCodes     = "status_codes"
+	AssertionBodyContains    = "body_contains"
+	AssertionBodyNotContains = "body_not_contains"
+	AssertionBodyMatches     = "body_matches"
+	AssertionBodyNotMatches  = "body_not_matches"
+	AssertionJSON            = "json"
+	AssertionHeaders         = "headers"
+	AssertionMaxBodySize     = "max_body_size"
+	AssertionMaxLatency      = "max_latency_ms"
+)
+
+// AssertionFailure descreve uma asserção não satisfeita pela resposta. Nas
+// asserções JSON, Expected é a expressão e Actual, o valor encontrado no
+// caminho, codificado em JSON.
+type AssertionFailure struct {
+	Assertion string `json:"assertion"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual,omitempty"`
+}
@@ -12,10 +12,24 @@ type CheckResult struct {
 	StatusCode    int       `json:"status_code" db:"status_code"`
 	ResponseTime  int64     `json:"response_time_ms" db:"response_time_ms"`
 	Error         string    `json:"error,omitempty" db:"error"`
+	ErrorKind     ErrorKind `json:"error_kind,omitempty" db:"error_kind"`
 	RedirectURL   string    `json:"redirect_url,omitempty" db:"redirect_url"`
 	RedirectCount int       `json:"redirect_count" db:"redirect_count"`
 	CheckedAt     time.Time `json:"checked_at" db:"checked_at"`
 	ContentLength int64     `json:"content_length" db:"content_length"`
 	Server        string    `json:"server,omitempty" db:"server"`
 	ResolvedIP    string    `json:"resolved_ip,omitempty" db:"resolved_ip"`
+	ResolvedIPs   []string  `json:"resolved_ips,omitempty" db:"resolved_ips"`
+	// UnexpectedIP indica que algum endereço resolvido não está em Domain.IP
+	UnexpectedIP bool `json:"unexpected_ip,omitempty" db:"unexpected_ip"`
+
+	RedirectChain *RedirectChain `json:"redirect_chain,omitempty" db:"redirect_chain"`
+	TLS           *TLSInfo       `json:"tls,omitempty" db:"tls"`
+	Timings       *Timings       `json:"timings,omitempty" db:"timings"`
+	// ExpectedStatus indica que StatusCode está em Assertions.StatusCodes do
+	// domínio; nesse caso a lista decide se o domínio está no ar
+	ExpectedStatus bool `json:"expected_status,omitempty" db:"expected_status"`
+	// AssertionFailures lista as asserções de Domain.Assertions não
+	// satisfeitas; quando há alguma, ErrorKind é ErrorKindAssertionFailed
+	AssertionFailures []AssertionFailure `json:"assertion_failures,omitempty" db:"assertion_failures"`
 }
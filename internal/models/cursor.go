@@ -0,0 +1,207 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidLimit é retornado quando o limite da página é menor que 1
+	ErrInvalidLimit = errors.New("limit must be greater than 0")
+	// ErrInvalidCursor é retornado para tokens malformados ou gerados com
+	// outra ordenação
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// CursorOptions define a página de uma listagem de domínios por cursor. Cursor
+// é o NextCursor da página anterior, ou vazio para começar do início; a
+// ordenação deve ser a mesma usada para gerá-lo.
+type CursorOptions struct {
+	Cursor string       `json:"cursor,omitempty"`
+	Limit  int          `json:"limit"`
+	SortBy SortField    `json:"sort_by,omitempty"`
+	Order  SortOrder    `json:"order,omitempty"`
+	Filter DomainFilter `json:"filter"`
+}
+
+// ListOptions retorna as opções de filtro e ordenação equivalentes, com os padrões aplicados
+func (o CursorOptions) ListOptions() ListOptions {
+	return ListOptions{Page: 1, PageSize: o.Limit, SortBy: o.SortBy, Order: o.Order, Filter: o.Filter}.WithDefaults()
+}
+
+// Decode valida as opções e retorna a ordenação e a posição do cursor, que é
+// nil na primeira página
+func (o CursorOptions) Decode() (ListOptions, *DomainCursor, error) {
+	if o.Limit < 1 {
+		return ListOptions{}, nil, ErrInvalidLimit
+	}
+
+	opts := o.ListOptions()
+	if err := opts.Validate(); err != nil {
+		return ListOptions{}, nil, err
+	}
+	if o.Cursor == "" {
+		return opts, nil, nil
+	}
+
+	cursor, err := DecodeDomainCursor(o.Cursor, opts)
+	if err != nil {
+		return ListOptions{}, nil, err
+	}
+	return opts, cursor, nil
+}
+
+// DomainCursorPage é uma página da listagem de domínios por cursor.
+// NextCursor fica vazio na última página.
+type DomainCursorPage struct {
+	Domains    []*Domain `json:"domains"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// NewDomainCursorPage monta a página a partir de até limit+1 domínios já
+// ordenados; o excedente indica que há uma próxima página
+func NewDomainCursorPage(domains []*Domain, opts ListOptions, limit int) *DomainCursorPage {
+	page := &DomainCursorPage{Domains: domains}
+	if len(domains) > limit {
+		page.Domains = domains[:limit]
+		page.NextCursor = EncodeCursor(NewDomainCursor(domains[limit-1], opts))
+	}
+	return page
+}
+
+// CheckResultCursorPage é uma página da listagem de resultados por cursor.
+// NextCursor fica vazio na última página.
+type CheckResultCursorPage struct {
+	Results    []*CheckResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// NewCheckResultCursorPage monta a página a partir de até limit+1 resultados
+// já ordenados; o excedente indica que há uma próxima página
+func NewCheckResultCursorPage(results []*CheckResult, limit int) *CheckResultCursorPage {
+	page := &CheckResultCursorPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		page.NextCursor = EncodeCursor(NewCheckResultCursor(results[limit-1]))
+	}
+	return page
+}
+
+// DomainCursor é a posição do último domínio de uma página: o valor do campo
+// de ordenação e o ID usado como desempate
+type DomainCursor struct {
+	SortBy SortField `json:"s"`
+	Order  SortOrder `json:"o"`
+	Time   time.Time `json:"t,omitempty"`
+	Value  string    `json:"v,omitempty"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// NewDomainCursor retorna o cursor que continua a listagem de opts após d
+func NewDomainCursor(d *Domain, opts ListOptions) *DomainCursor {
+	c := &DomainCursor{SortBy: opts.SortBy, Order: opts.Order, ID: d.ID}
+	switch opts.SortBy {
+	case SortByUpdatedAt:
+		c.Time = d.UpdatedAt
+	case SortByName:
+		c.Value = d.Name
+	case SortByURL:
+		c.Value = d.URL
+	case SortByStatus:
+		c.Value = string(d.Status)
+	case SortByLastCheckedAt:
+		c.Time = d.LastCheckedAt
+	default:
+		c.Time = d.CreatedAt
+	}
+	return c
+}
+
+// DecodeDomainCursor lê um token de EncodeCursor e verifica se ele foi gerado
+// com a ordenação de opts
+func DecodeDomainCursor(token string, opts ListOptions) (*DomainCursor, error) {
+	var c DomainCursor
+	if err := decodeCursor(token, &c); err != nil {
+		return nil, err
+	}
+	if c.SortBy != opts.SortBy || c.Order != opts.Order {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Domain retorna um domínio com apenas o campo de ordenação e o ID
+// preenchidos, para comparar com ListOptions.Compare
+func (c *DomainCursor) Domain() *Domain {
+	return &Domain{
+		ID:            c.ID,
+		Name:          c.Value,
+		URL:           c.Value,
+		Status:        DomainStatus(c.Value),
+		CreatedAt:     c.Time,
+		UpdatedAt:     c.Time,
+		LastCheckedAt: c.Time,
+	}
+}
+
+// CheckResultCursor é a posição do último resultado de uma página. Os
+// resultados vêm do mais recente ao mais antigo, com o ID como desempate.
+type CheckResultCursor struct {
+	CheckedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// NewCheckResultCursor retorna o cursor que continua a listagem após r
+func NewCheckResultCursor(r *CheckResult) *CheckResultCursor {
+	return &CheckResultCursor{CheckedAt: r.CheckedAt, ID: r.ID}
+}
+
+// DecodeCheckResultCursor lê um token de EncodeCursor
+func DecodeCheckResultCursor(token string) (*CheckResultCursor, error) {
+	var c CheckResultCursor
+	if err := decodeCursor(token, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Follows informa se r vem depois do cursor na listagem, isto é, se é mais
+// antigo ou, no mesmo horário, tem ID menor
+func (c *CheckResultCursor) Follows(r *CheckResult) bool {
+	return CompareCheckResults(r, &CheckResult{CheckedAt: c.CheckedAt, ID: c.ID}) < 0
+}
+
+// CompareCheckResults ordena os resultados por CheckedAt e depois por ID.
+// Retorna um valor negativo se a é anterior a b.
+func CompareCheckResults(a, b *CheckResult) int {
+	if c := a.CheckedAt.Compare(b.CheckedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// EncodeCursor serializa um cursor em um token opaco para URLs
+func EncodeCursor(cursor any) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		// Os cursores só têm campos serializáveis
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DNSChangeEvent registra uma mudança nos endereços resolvidos de um domínio.
+// O primeiro evento de cada domínio tem PreviousIPs vazio e marca o início do
+// histórico.
+type DNSChangeEvent struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	DomainID        uuid.UUID `json:"domain_id" db:"domain_id"`
+	PreviousIPs     []string  `json:"previous_ips" db:"previous_ips"`
+	CurrentIPs      []string  `json:"current_ips" db:"current_ips"`
+	ExpectedIPs     []string  `json:"expected_ips,omitempty" db:"expected_ips"`
+	MatchesExpected bool      `json:"matches_expected" db:"matches_expected"`
+	DetectedAt      time.Time `json:"detected_at" db:"detected_at"`
+}
@@ -1,17 +1,76 @@
 package models
 
 import (
+	"net"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
 
 type Domain struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	URL       string    `json:"url" db:"url"`
-	Timeout   int       `json:"timeout" db:"timeout"`
-	IP        string    `json:"ip,omitempty" db:"ip"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at"`
+	ID   uuid.UUID `json:"id" db:"id"`
+	Name string    `json:"name" db:"name"`
+	URL  string    `json:"url" db:"url"`
+	// Timeout é o prazo de cada verificação, em segundos, da resolução DNS à
+	// leitura da resposta; zero usa o padrão do checker
+	Timeout int `json:"timeout" db:"timeout"`
+	// Interval é o intervalo entre verificações, em segundos
+	Interval int      `json:"interval,omitempty" db:"interval"`
+	IP       string   `json:"ip,omitempty" db:"ip"`
+	Tags     []string `json:"tags,omitempty" db:"tags"`
+	// Assertions são as verificações do conteúdo da resposta; nil aceita
+	// qualquer resposta 2xx ou 3xx
+	Assertions *Assertions `json:"assertions,omitempty" db:"assertions"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at,omitempty" db:"updated_at"`
+
+	// Status e LastCheckedAt refletem a verificação mais recente e são
+	// mantidos pelo storage ao gravar resultados; são ignorados em updates
+	Status        DomainStatus `json:"status,omitempty" db:"status"`
+	LastCheckedAt time.Time    `json:"last_checked_at,omitempty" db:"last_checked_at"`
+}
+
+// HasTag informa se o domínio tem a tag informada
+func (d *Domain) HasTag(tag string) bool {
+	return slices.Contains(d.Tags, tag)
+}
+
+// ExpectedIPs interpreta Domain.IP como a lista de endereços esperados,
+// separados por vírgula ou espaço. Endereços inválidos são ignorados.
+func (d *Domain) ExpectedIPs() []string {
+	fields := strings.FieldsFunc(d.IP, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	ips := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if ip := net.ParseIP(field); ip != nil {
+			ips = append(ips, ip.String())
+		}
+	}
+	return ips
+}
+
+// MatchesExpectedIPs informa se todos os endereços resolvidos pertencem ao
+// conjunto esperado. Sem endereços esperados, qualquer resposta é aceita.
+func (d *Domain) MatchesExpectedIPs(resolved []string) bool {
+	expected := d.ExpectedIPs()
+	if len(expected) == 0 {
+		return true
+	}
+
+	allowed := make(map[string]bool, len(expected))
+	for _, ip := range expected {
+		allowed[ip] = true
+	}
+
+	for _, ip := range resolved {
+		if parsed := net.ParseIP(ip); parsed == nil || !allowed[parsed.String()] {
+			return false
+		}
+	}
+	return true
 }
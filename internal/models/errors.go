@@ -0,0 +1,37 @@
+package models
+
+// ErrorKind classifica a falha registrada em CheckResult.Error
+type ErrorKind string
+
+const (
+	ErrorKindTLSHostnameMismatch ErrorKind = "tls_hostname_mismatch"
+	ErrorKindTLSUntrustedChain   ErrorKind = "tls_untrusted_chain"
+	ErrorKindTLSExpired          ErrorKind = "tls_expired"
+	ErrorKindTLSNotYetValid      ErrorKind = "tls_not_yet_valid"
+
+	ErrorKindDNS         ErrorKind = "dns_error"
+	ErrorKindDNSNXDomain ErrorKind = "dns_nxdomain"
+	ErrorKindDNSServFail ErrorKind = "dns_servfail"
+	ErrorKindDNSTimeout  ErrorKind = "dns_timeout"
+	ErrorKindDNSRefused  ErrorKind = "dns_refused"
+	ErrorKindDNSNoAnswer ErrorKind = "dns_no_answer"
+
+	// Expiração do prazo da verificação (Domain.Timeout), pela etapa em que
+	// ocorreu. Durante a resolução DNS, a classificação é ErrorKindDNSTimeout.
+	ErrorKindConnectTimeout  ErrorKind = "connect_timeout"
+	ErrorKindTLSTimeout      ErrorKind = "tls_timeout"
+	ErrorKindResponseTimeout ErrorKind = "response_timeout"
+
+	// ErrorKindAssertionFailed indica que a resposta não satisfez
+	// Domain.Assertions; os detalhes ficam em CheckResult.AssertionFailures
+	ErrorKindAssertionFailed ErrorKind = "assertion_failed"
+)
+
+// IsTimeout indica se a falha foi a expiração do prazo da verificação
+func (k ErrorKind) IsTimeout() bool {
+	switch k {
+	case ErrorKindDNSTimeout, ErrorKindConnectTimeout, ErrorKindTLSTimeout, ErrorKindResponseTimeout:
+		return true
+	}
+	return false
+}
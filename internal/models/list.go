@@ -0,0 +1,211 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPage é retornado quando page ou pageSize são menores que 1
+	ErrInvalidPage = errors.New("page and pageSize must be greater than 0")
+	// ErrInvalidSort é retornado para campo ou direção de ordenação desconhecidos
+	ErrInvalidSort = errors.New("invalid sort field or order")
+	// ErrInvalidFilter é retornado para status desconhecido ou idades inválidas
+	ErrInvalidFilter = errors.New("invalid domain filter")
+)
+
+// SortField é o campo usado para ordenar a listagem de domínios
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByName      SortField = "name"
+	SortByURL       SortField = "url"
+	SortByStatus    SortField = "status"
+	// SortByLastCheckedAt ordena pela verificação mais recente; domínios
+	// nunca verificados vêm antes na ordem ascendente
+	SortByLastCheckedAt SortField = "last_checked_at"
+)
+
+// SortOrder é a direção da ordenação
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListOptions define a página, o filtro e a ordenação da listagem de
+// domínios. Sem ordenação informada, os domínios vêm em ordem de criação.
+// Empates são desfeitos pelo ID, na mesma direção, para que a ordem seja
+// sempre total e as páginas não se sobreponham.
+type ListOptions struct {
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	SortBy   SortField    `json:"sort_by,omitempty"`
+	Order    SortOrder    `json:"order,omitempty"`
+	Filter   DomainFilter `json:"filter"`
+}
+
+// WithDefaults retorna uma cópia com a ordenação padrão nos campos vazios
+func (o ListOptions) WithDefaults() ListOptions {
+	if o.SortBy == "" {
+		o.SortBy = SortByCreatedAt
+	}
+	if o.Order == "" {
+		o.Order = SortAsc
+	}
+	return o
+}
+
+// Validate verifica a paginação, a ordenação e o filtro. Deve ser chamado após WithDefaults.
+func (o ListOptions) Validate() error {
+	if o.Page < 1 || o.PageSize < 1 {
+		return ErrInvalidPage
+	}
+
+	switch o.SortBy {
+	case SortByCreatedAt, SortByUpdatedAt, SortByName, SortByURL, SortByStatus, SortByLastCheckedAt:
+	default:
+		return ErrInvalidSort
+	}
+
+	if o.Order != SortAsc && o.Order != SortDesc {
+		return ErrInvalidSort
+	}
+	return o.Filter.Validate()
+}
+
+// Offset retorna quantos domínios vêm antes da página
+func (o ListOptions) Offset() int {
+	return (o.Page - 1) * o.PageSize
+}
+
+// Compare ordena a e b segundo as opções, para backends que ordenam em
+// memória. Retorna um valor negativo se a vem antes de b.
+func (o ListOptions) Compare(a, b *Domain) int {
+	var c int
+	switch o.SortBy {
+	case SortByUpdatedAt:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	case SortByName:
+		c = strings.Compare(a.Name, b.Name)
+	case SortByURL:
+		c = strings.Compare(a.URL, b.URL)
+	case SortByStatus:
+		c = strings.Compare(string(a.Status), string(b.Status))
+	case SortByLastCheckedAt:
+		c = a.LastCheckedAt.Compare(b.LastCheckedAt)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+
+	if c == 0 {
+		c = bytes.Compare(a.ID[:], b.ID[:])
+	}
+	if o.Order == SortDesc {
+		c = -c
+	}
+	return c
+}
+
+// DomainFilter restringe a listagem de domínios. Campos vazios não filtram.
+type DomainFilter struct {
+	// Search busca o texto no nome ou na URL, sem diferenciar maiúsculas
+	Search string `json:"search,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+	// Status também aceita StatusUnknown, para domínios nunca verificados
+	Status DomainStatus `json:"status,omitempty"`
+
+	// CreatedAfter é inclusivo e CreatedBefore é exclusivo
+	CreatedAfter  time.Time `json:"created_after,omitempty"`
+	CreatedBefore time.Time `json:"created_before,omitempty"`
+
+	// MinCheckAge e MaxCheckAge limitam há quanto tempo foi a verificação
+	// mais recente, inclusive. Domínios nunca verificados não são incluídos
+	// quando algum dos dois é informado.
+	MinCheckAge time.Duration `json:"min_check_age,omitempty"`
+	MaxCheckAge time.Duration `json:"max_check_age,omitempty"`
+}
+
+// Validate verifica o status e as idades do filtro
+func (f DomainFilter) Validate() error {
+	if f.Status != "" && !f.Status.Valid() {
+		return ErrInvalidFilter
+	}
+	if f.MinCheckAge < 0 || f.MaxCheckAge < 0 {
+		return ErrInvalidFilter
+	}
+	if f.MaxCheckAge > 0 && f.MinCheckAge > f.MaxCheckAge {
+		return ErrInvalidFilter
+	}
+	return nil
+}
+
+// LastCheckRange converte as idades do filtro no intervalo [from, to] de
+// LastCheckedAt em relação a now. Limites zerados não restringem o intervalo.
+func (f DomainFilter) LastCheckRange(now time.Time) (from, to time.Time) {
+	if f.MaxCheckAge > 0 {
+		from = now.Add(-f.MaxCheckAge)
+	}
+	if f.MinCheckAge > 0 {
+		to = now.Add(-f.MinCheckAge)
+	}
+	return from, to
+}
+
+// Matches informa se d passa pelo filtro, com as idades calculadas em
+// relação a now. Usado pelos backends que filtram em memória.
+func (f DomainFilter) Matches(d *Domain, now time.Time) bool {
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(d.Name), search) && !strings.Contains(strings.ToLower(d.URL), search) {
+			return false
+		}
+	}
+	if f.Tag != "" && !d.HasTag(f.Tag) {
+		return false
+	}
+	if f.Status != "" && d.Status != f.Status {
+		return false
+	}
+
+	if !f.CreatedAfter.IsZero() && d.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !d.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+
+	from, to := f.LastCheckRange(now)
+	if (!from.IsZero() || !to.IsZero()) && d.LastCheckedAt.IsZero() {
+		return false
+	}
+	if !from.IsZero() && d.LastCheckedAt.Before(from) {
+		return false
+	}
+	if !to.IsZero() && d.LastCheckedAt.After(to) {
+		return false
+	}
+	return true
+}
+
+// DomainPage é uma página da listagem de domínios
+type DomainPage struct {
+	Domains  []*Domain `json:"domains"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	// Total é o número de domínios que passam pelo filtro, em todas as páginas
+	Total int `json:"total"`
+}
+
+// TotalPages retorna o número de páginas da listagem
+func (p *DomainPage) TotalPages() int {
+	if p.PageSize < 1 {
+		return 0
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
@@ -0,0 +1,18 @@
+package models
+
+// RedirectHop representa uma requisição feita durante a cadeia de redirecionamentos
+type RedirectHop struct {
+	URL          string `json:"url"`
+	StatusCode   int    `json:"status_code"`
+	ResponseTime int64  `json:"response_time_ms"`
+	Location     string `json:"location,omitempty"`
+}
+
+// RedirectChain registra todos os saltos seguidos pelo checker e as anomalias
+// encontradas no caminho
+type RedirectChain struct {
+	Hops        []RedirectHop `json:"hops"`
+	Loop        bool          `json:"loop"`
+	Downgrade   bool          `json:"downgrade"`
+	CrossDomain bool          `json:"cross_domain"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// DomainStatus é a situação do domínio na verificação mais recente
+type DomainStatus string
+
+const (
+	StatusUp   DomainStatus = "up"
+	StatusDown DomainStatus = "down"
+	// StatusUnknown indica que o domínio ainda não foi verificado
+	StatusUnknown DomainStatus = "unknown"
+)
+
+// Valid informa se s é um dos status conhecidos
+func (s DomainStatus) Valid() bool {
+	return s == StatusUp || s == StatusDown || s == StatusUnknown
+}
+
+// Status classifica o resultado: o domínio está no ar quando a verificação
+// não teve erro e terminou com status HTTP 2xx ou 3xx, ou com um dos status
+// aceitos por Assertions.StatusCodes (ExpectedStatus).
+//
+// Os backends SQL repetem a regra 2xx/3xx na migração que preenche o status
+// dos domínios já existentes.
+func (r *CheckResult) Status() DomainStatus {
+	if r.Error == "" && (r.ExpectedStatus || r.StatusCode >= 200 && r.StatusCode < 400) {
+		return StatusUp
+	}
+	return StatusDown
+}
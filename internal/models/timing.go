@@ -0,0 +1,13 @@
+package models
+
+// Timings detalha a duração de cada etapa da verificação, em milissegundos.
+// Etapas que não ocorreram ficam zeradas, como TLSHandshake em URLs http.
+// Com redirecionamentos, cada campo soma a etapa em todos os saltos.
+type Timings struct {
+	DNSLookup    int64 `json:"dns_lookup_ms"`
+	TCPConnect   int64 `json:"tcp_connect_ms"`
+	TLSHandshake int64 `json:"tls_handshake_ms"`
+	// TimeToFirstByte vai do envio da requisição ao primeiro byte da resposta
+	TimeToFirstByte int64 `json:"time_to_first_byte_ms"`
+	ContentTransfer int64 `json:"content_transfer_ms"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TLSInfo descreve o certificado e a sessão TLS negociados durante a verificação
+type TLSInfo struct {
+	Subject       string    `json:"subject"`
+	SANs          []string  `json:"sans,omitempty"`
+	Issuer        string    `json:"issuer"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysToExpiry  int       `json:"days_to_expiry"`
+	ChainValid    bool      `json:"chain_valid"`
+	HostnameValid bool      `json:"hostname_valid"`
+	Version       string    `json:"version"`
+	CipherSuite   string    `json:"cipher_suite"`
+}
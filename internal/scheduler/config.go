@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+const (
+	// DefaultWorkers é o número de verificações simultâneas quando Config.Workers é zero
+	DefaultWorkers = 10
+	// DefaultInterval é usado para domínios sem Interval
+	DefaultInterval = time.Minute
+	// DefaultJitter é a variação aplicada a cada intervalo quando Config.Jitter é zero
+	DefaultJitter = 0.1
+	// DefaultPageSize é o tamanho das páginas lidas do storage no Start
+	DefaultPageSize = 100
+)
+
+// ResultHandler recebe o resultado de cada verificação executada pelo scheduler,
+// já gravado no storage. err é o erro da verificação ou, na falta dele, o da
+// gravação do resultado ou da detecção de mudanças de DNS.
+type ResultHandler func(domain *models.Domain, result *models.CheckResult, err error)
+
+// Config define o comportamento do scheduler
+type Config struct {
+	// Workers limita quantas verificações rodam ao mesmo tempo
+	Workers         int           `json:"workers,omitempty"`
+	DefaultInterval time.Duration `json:"default_interval,omitempty"`
+	// Jitter é a fração do intervalo usada para variar cada agendamento, entre
+	// 0 e 1. Use um valor negativo para agendar sem variação.
+	Jitter   float64 `json:"jitter,omitempty"`
+	PageSize int     `json:"page_size,omitempty"`
+
+	// OnResult, quando informado, recebe o resultado de cada verificação
+	OnResult ResultHandler `json:"-"`
+}
+
+// DefaultConfig retorna a configuração padrão do scheduler
+func DefaultConfig() *Config {
+	return &Config{
+		Workers:         DefaultWorkers,
+		DefaultInterval: DefaultInterval,
+		Jitter:          DefaultJitter,
+		PageSize:        DefaultPageSize,
+	}
+}
+
+// withDefaults preenche os campos não informados com os valores padrão
+func (c *Config) withDefaults() *Config {
+	cfg := DefaultConfig()
+	if c == nil {
+		return cfg
+	}
+
+	if c.Workers > 0 {
+		cfg.Workers = c.Workers
+	}
+	if c.DefaultInterval > 0 {
+		cfg.DefaultInterval = c.DefaultInterval
+	}
+	switch {
+	case c.Jitter < 0:
+		cfg.Jitter = 0
+	case c.Jitter > 1:
+		cfg.Jitter = 1
+	case c.Jitter > 0:
+		cfg.Jitter = c.Jitter
+	}
+	if c.PageSize > 0 {
+		cfg.PageSize = c.PageSize
+	}
+
+	cfg.OnResult = c.OnResult
+
+	return cfg
+}
@@ -0,0 +1,7 @@
+package scheduler
+
+import "errors"
+
+var (
+	ErrAlreadyRunning = errors.New("scheduler already running")
+)
@@ -0,0 +1,18 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/luizhreis/domain-watcher/internal/domain"
+)
+
+type Scheduler interface {
+	// Start carrega os domínios do storage e inicia as verificações periódicas.
+	// O scheduler para quando ctx é cancelado ou Stop é chamado.
+	Start(ctx context.Context) error
+	// Stop interrompe o agendamento e aguarda as verificações em andamento
+	Stop()
+	// HandleDomainEvent aplica uma alteração de domínio ao agendamento; pode
+	// ser registrado com domain.Domain.Subscribe
+	HandleDomainEvent(event domain.Event)
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// entry é o agendamento de um domínio. Entradas em execução ficam fora da fila.
+type entry struct {
+	domain  *models.Domain
+	next    time.Time
+	index   int
+	running bool
+}
+
+// entryQueue é um heap de entradas ordenado pela próxima execução
+type entryQueue []*entry
+
+func (q entryQueue) Len() int { return len(q) }
+
+func (q entryQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q entryQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *entryQueue) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *entryQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*q = old[:n-1]
+	return e
+}
@@ -0,0 +1,351 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/checker"
+	"github.com/luizhreis/domain-watcher/internal/dnschange"
+	"github.com/luizhreis/domain-watcher/internal/domain"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage"
+)
+
+// idleWait é a espera do laço de agendamento quando a fila está vazia; novos
+// domínios acordam o laço antes disso
+const idleWait = time.Hour
+
+type scheduler struct {
+	storage  storage.Storage
+	checker  checker.Checker
+	detector dnschange.Detector
+	config   *Config
+	now      func() time.Time
+	random   func() float64
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]*entry
+	queue   entryQueue
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	// done é fechado quando a execução iniciada pelo Start termina
+	done chan struct{}
+}
+
+var _ Scheduler = (*scheduler)(nil)
+
+func NewScheduler(storage storage.Storage, checker checker.Checker) Scheduler {
+	return NewSchedulerWithConfig(storage, checker, nil)
+}
+
+// NewSchedulerWithConfig cria um scheduler com configuração personalizada.
+// Campos não informados em config usam os valores de DefaultConfig.
+func NewSchedulerWithConfig(storage storage.Storage, checker checker.Checker, config *Config) Scheduler {
+	return &scheduler{
+		storage:  storage,
+		checker:  checker,
+		detector: dnschange.NewDetector(storage),
+		config:   config.withDefaults(),
+		now:      time.Now,
+		random:   rand.Float64,
+		entries:  make(map[uuid.UUID]*entry),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Start carrega os domínios e inicia o laço de agendamento e os workers. A
+// primeira verificação de cada domínio é distribuída ao longo do seu intervalo.
+// O agendamento passa a ter exatamente os domínios lidos do storage, de modo
+// que os excluídos enquanto o scheduler estava parado são descartados.
+func (s *scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	running := s.cancel != nil
+	s.mu.Unlock()
+	if running {
+		return ErrAlreadyRunning
+	}
+
+	// A leitura acontece sem s.mu para não bloquear HandleDomainEvent e Stop
+	domains, err := s.loadDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return ErrAlreadyRunning
+	}
+
+	now := s.now()
+	loaded := make(map[uuid.UUID]bool, len(domains))
+	for _, d := range domains {
+		loaded[d.ID] = true
+		s.schedule(d, now.Add(s.spread(s.interval(d), 1)))
+	}
+	for id := range s.entries {
+		if !loaded[id] {
+			s.remove(id)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+	jobs := make(chan *models.Domain)
+
+	var wg sync.WaitGroup
+	wg.Add(1 + s.config.Workers)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		s.run(ctx, jobs)
+	}()
+	for range s.config.Workers {
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				s.check(ctx, d)
+			}
+		}()
+	}
+
+	// Ao fim da execução, seja pelo Stop ou pelo cancelamento de ctx, o
+	// scheduler volta a aceitar Start
+	go func() {
+		wg.Wait()
+		cancel()
+
+		s.mu.Lock()
+		s.cancel, s.done = nil, nil
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	return nil
+}
+
+// Stop cancela o agendamento e aguarda as verificações em andamento
+func (s *scheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// HandleDomainEvent agenda domínios novos para logo, aplica a domínios
+// alterados o novo intervalo e remove os excluídos
+func (s *scheduler) HandleDomainEvent(event domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	switch event.Type {
+	case domain.EventCreated:
+		if event.Domain != nil {
+			s.schedule(event.Domain, now.Add(s.spread(s.interval(event.Domain), s.config.Jitter)))
+		}
+	case domain.EventUpdated:
+		if event.Domain == nil {
+			return
+		}
+
+		// Mantém a execução já agendada se ela vier antes do novo intervalo
+		next := now.Add(s.jittered(s.interval(event.Domain)))
+		if e, ok := s.entries[event.DomainID]; ok && e.next.Before(next) {
+			next = e.next
+		}
+		s.schedule(event.Domain, next)
+	case domain.EventDeleted:
+		s.remove(event.DomainID)
+	}
+}
+
+// run despacha os domínios vencidos para os workers até ctx ser cancelado
+func (s *scheduler) run(ctx context.Context, jobs chan<- *models.Domain) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		due, wait := s.due()
+		for i, d := range due {
+			select {
+			case jobs <- d:
+			case <-ctx.Done():
+				for _, pending := range due[i:] {
+					s.reschedule(pending.ID)
+				}
+				return
+			}
+		}
+
+		timer.Reset(wait)
+	}
+}
+
+// due retira da fila os domínios vencidos, marcando-os em execução, e retorna
+// quanto falta para o próximo
+func (s *scheduler) due() ([]*models.Domain, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	var due []*models.Domain
+	for len(s.queue) > 0 && !s.queue[0].next.After(now) {
+		e := heap.Pop(&s.queue).(*entry)
+		e.running = true
+		due = append(due, e.domain)
+	}
+
+	wait := idleWait
+	if len(s.queue) > 0 {
+		wait = s.queue[0].next.Sub(now)
+	}
+	return due, wait
+}
+
+// check executa a verificação, grava o resultado, registra mudanças nos IPs
+// resolvidos e devolve o domínio à fila. Verificações interrompidas pelo
+// cancelamento de ctx não são registradas; um resultado já obtido é gravado
+// mesmo durante o Stop, para não se perder.
+func (s *scheduler) check(ctx context.Context, d *models.Domain) {
+	defer s.reschedule(d.ID)
+
+	result, err := s.checker.CheckDomainContext(ctx, d)
+	if result == nil && ctx.Err() != nil {
+		return
+	}
+	if result != nil {
+		saveErr := s.storage.SaveCheckResultContext(context.WithoutCancel(ctx), result)
+		if saveErr == nil {
+			_, saveErr = s.detector.Detect(d, result)
+		}
+		if saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	if s.config.OnResult != nil {
+		s.config.OnResult(d, result, err)
+	}
+}
+
+// reschedule agenda a próxima verificação de um domínio que estava em
+// execução. Domínios excluídos durante a verificação são descartados.
+func (s *scheduler) reschedule(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok || !e.running {
+		return
+	}
+
+	e.running = false
+	e.next = s.now().Add(s.jittered(s.interval(e.domain)))
+	heap.Push(&s.queue, e)
+	s.signal()
+}
+
+// schedule cria ou atualiza a entrada do domínio. Entradas em execução só
+// recebem o domínio atualizado e são reagendadas ao fim da verificação.
+// Deve ser chamado com s.mu travado.
+func (s *scheduler) schedule(d *models.Domain, next time.Time) {
+	e, ok := s.entries[d.ID]
+	if !ok {
+		e = &entry{index: -1}
+		s.entries[d.ID] = e
+	}
+
+	e.domain = d
+	if e.running {
+		return
+	}
+
+	e.next = next
+	if e.index < 0 {
+		heap.Push(&s.queue, e)
+	} else {
+		heap.Fix(&s.queue, e.index)
+	}
+	s.signal()
+}
+
+// remove descarta o agendamento do domínio. Deve ser chamado com s.mu travado.
+func (s *scheduler) remove(id uuid.UUID) {
+	e, ok := s.entries[id]
+	if !ok {
+		return
+	}
+
+	delete(s.entries, id)
+	if e.index >= 0 {
+		heap.Remove(&s.queue, e.index)
+	}
+}
+
+// signal acorda o laço de agendamento sem bloquear
+func (s *scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loadDomains lê todos os domínios do storage, página por página
+func (s *scheduler) loadDomains(ctx context.Context) ([]*models.Domain, error) {
+	var domains []*models.Domain
+	for page := 1; ; page++ {
+		batch, err := s.storage.ListDomainsContext(ctx, models.ListOptions{Page: page, PageSize: s.config.PageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range batch.Domains {
+			snapshot := *d
+			domains = append(domains, &snapshot)
+		}
+
+		if len(batch.Domains) < s.config.PageSize || len(domains) >= batch.Total {
+			return domains, nil
+		}
+	}
+}
+
+// interval retorna o intervalo de verificação do domínio
+func (s *scheduler) interval(d *models.Domain) time.Duration {
+	if d.Interval <= 0 {
+		return s.config.DefaultInterval
+	}
+	return time.Duration(d.Interval) * time.Second
+}
+
+// jittered varia interval em até ±Jitter para espalhar as verificações
+func (s *scheduler) jittered(interval time.Duration) time.Duration {
+	return interval + time.Duration((2*s.random()-1)*s.config.Jitter*float64(interval))
+}
+
+// spread retorna um atraso aleatório entre zero e fraction do intervalo
+func (s *scheduler) spread(interval time.Duration, fraction float64) time.Duration {
+	return time.Duration(s.random() * fraction * float64(interval))
+}
@@ -0,0 +1,469 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/domain"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// mockChecker conta as verificações por domínio e a concorrência máxima
+type mockChecker struct {
+	mu        sync.Mutex
+	calls     map[uuid.UUID]int
+	running   int
+	maxActive int
+	delay     time.Duration
+	// ips é o IP resolvido em cada verificação; o último se repete
+	ips []string
+}
+
+func newMockChecker() *mockChecker {
+	return &mockChecker{calls: make(map[uuid.UUID]int)}
+}
+
+func (m *mockChecker) CheckDomain(d *models.Domain) (*models.CheckResult, error) {
+	return m.CheckDomainContext(context.Background(), d)
+}
+
+// CheckDomainContext espera delay ou o cancelamento de ctx, como um checker real
+func (m *mockChecker) CheckDomainContext(ctx context.Context, d *models.Domain) (*models.CheckResult, error) {
+	m.mu.Lock()
+	m.calls[d.ID]++
+	m.running++
+	m.maxActive = max(m.maxActive, m.running)
+	var ip string
+	if len(m.ips) > 0 {
+		ip = m.ips[min(m.calls[d.ID], len(m.ips))-1]
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.running--
+		m.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &models.CheckResult{ID: uuid.New(), DomainID: d.ID, ResolvedIP: ip, CheckedAt: time.Now()}, nil
+}
+
+func (m *mockChecker) count(id uuid.UUID) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[id]
+}
+
+// waitFor aguarda condition ficar verdadeira ou falha o teste
+func waitFor(t *testing.T, condition func() bool, message string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestScheduler(t *testing.T, storage *helpers.MockStorage, checker *mockChecker, config *Config) *scheduler {
+	t.Helper()
+
+	s := NewSchedulerWithConfig(storage, checker, config).(*scheduler)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestNewScheduler(t *testing.T) {
+	s := NewScheduler(helpers.NewMockStorage(), newMockChecker()).(*scheduler)
+
+	if s.config.Workers != DefaultWorkers || s.config.DefaultInterval != DefaultInterval {
+		t.Errorf("Expected default config, got %+v", s.config)
+	}
+}
+
+// TestSchedulerRunsChecks testa as verificações periódicas dos domínios do storage
+func TestSchedulerRunsChecks(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	first := helpers.NewTestDomainBuilder().WithURL("first.example.com").Build()
+	second := helpers.NewTestDomainBuilder().WithURL("second.example.com").Build()
+	_, _ = storage.CreateDomain(first)
+	_, _ = storage.CreateDomain(second)
+
+	var (
+		mu      sync.Mutex
+		results []*models.CheckResult
+	)
+	checker := newMockChecker()
+	s := newTestScheduler(t, storage, checker, &Config{
+		DefaultInterval: 20 * time.Millisecond,
+		Jitter:          -1,
+		OnResult: func(d *models.Domain, result *models.CheckResult, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		},
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	waitFor(t, func() bool {
+		return checker.count(first.ID) >= 3 && checker.count(second.ID) >= 3
+	}, "Expected both domains to be checked repeatedly")
+
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(results) < 6 {
+		t.Errorf("Expected OnResult for every check, got %d results", len(results))
+	}
+
+	// Todos os resultados entregues ao OnResult foram gravados
+	for _, result := range results {
+		saved, err := storage.ListCheckResults(result.DomainID, time.Time{}, time.Time{}, 1, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(saved, result) {
+			t.Errorf("Expected result %v to be saved", result.ID)
+		}
+	}
+}
+
+// TestSchedulerDetectsDNSChanges testa que cada resultado passa pelo detector
+// de mudanças de DNS
+func TestSchedulerDetectsDNSChanges(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	d := helpers.NewTestDomainBuilder().Build()
+	_, _ = storage.CreateDomain(d)
+
+	checker := newMockChecker()
+	checker.ips = []string{"192.0.2.10", "192.0.2.10", "192.0.2.20"}
+	s := newTestScheduler(t, storage, checker, &Config{DefaultInterval: 10 * time.Millisecond, Jitter: -1})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return checker.count(d.ID) >= 4 }, "Expected domain to be checked repeatedly")
+	s.Stop()
+
+	// A primeira observação e a troca de IP geram eventos; repetições, não
+	changes, err := storage.ListDNSChanges(d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 DNS changes, got %d", len(changes))
+	}
+	last := changes[1]
+	if !slices.Equal(last.PreviousIPs, []string{"192.0.2.10"}) || !slices.Equal(last.CurrentIPs, []string{"192.0.2.20"}) {
+		t.Errorf("Expected change from 192.0.2.10 to 192.0.2.20, got %v -> %v", last.PreviousIPs, last.CurrentIPs)
+	}
+}
+
+// TestSchedulerWorkerPool testa o limite de verificações simultâneas
+func TestSchedulerWorkerPool(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	var ids []uuid.UUID
+	for range 6 {
+		d := helpers.NewTestDomainBuilder().Build()
+		_, _ = storage.CreateDomain(d)
+		ids = append(ids, d.ID)
+	}
+
+	checker := newMockChecker()
+	checker.delay = 20 * time.Millisecond
+	s := newTestScheduler(t, storage, checker, &Config{
+		Workers:         2,
+		DefaultInterval: 10 * time.Millisecond,
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		for _, id := range ids {
+			if checker.count(id) == 0 {
+				return false
+			}
+		}
+		return true
+	}, "Expected all domains to be checked")
+
+	s.Stop()
+
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+	if checker.maxActive > 2 {
+		t.Errorf("Expected at most 2 concurrent checks, got %d", checker.maxActive)
+	}
+}
+
+// TestSchedulerDomainEvents testa que alterações feitas via domain.Domain são aplicadas
+func TestSchedulerDomainEvents(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	checker := newMockChecker()
+	s := newTestScheduler(t, storage, checker, &Config{DefaultInterval: 20 * time.Millisecond})
+
+	service := domain.NewDomain(storage)
+	service.Subscribe(s.HandleDomainEvent)
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	d := helpers.NewTestDomainBuilder().Build()
+	if _, err := service.Create(d); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return checker.count(d.ID) >= 2 }, "Expected created domain to be checked")
+
+	// Um intervalo maior adia as próximas verificações
+	updated := *d
+	updated.Interval = 3600
+	if err := service.Update(&updated); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e := s.entries[d.ID]
+		return !e.running && time.Until(e.next) > time.Minute
+	}, "Expected updated interval to be applied")
+
+	if err := service.Delete(d.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	_, scheduled := s.entries[d.ID]
+	queued := len(s.queue)
+	s.mu.Unlock()
+
+	if scheduled || queued != 0 {
+		t.Errorf("Expected deleted domain to be unscheduled, got %d queued", queued)
+	}
+}
+
+// TestSchedulerDeleteWhileRunning testa que um domínio excluído durante a verificação não volta à fila
+func TestSchedulerDeleteWhileRunning(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	d := helpers.NewTestDomainBuilder().Build()
+	_, _ = storage.CreateDomain(d)
+
+	checker := newMockChecker()
+	checker.delay = 50 * time.Millisecond
+	s := newTestScheduler(t, storage, checker, &Config{DefaultInterval: time.Millisecond})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.entries[d.ID].running
+	}, "Expected domain to be running")
+
+	s.HandleDomainEvent(domain.Event{Type: domain.EventDeleted, DomainID: d.ID})
+	calls := checker.count(d.ID)
+
+	time.Sleep(100 * time.Millisecond)
+	if checker.count(d.ID) != calls {
+		t.Errorf("Expected no checks after delete, got %d more", checker.count(d.ID)-calls)
+	}
+}
+
+// TestSchedulerStopCancelsChecks testa que Stop interrompe as verificações em
+// andamento sem registrá-las
+func TestSchedulerStopCancelsChecks(t *testing.T) {
+	storage := helpers.NewMockStorage()
+	d := helpers.NewTestDomainBuilder().Build()
+	_, _ = storage.CreateDomain(d)
+
+	var reported int
+	checker := newMockChecker()
+	checker.delay = time.Hour
+	s := newTestScheduler(t, storage, checker, &Config{
+		DefaultInterval: time.Millisecond,
+		OnResult: func(*models.Domain, *models.CheckResult, error) {
+			reported++
+		},
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return checker.count(d.ID) > 0 }, "Expected domain to be checked")
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Stop to cancel the running check")
+	}
+
+	if reported != 0 {
+		t.Errorf("Expected canceled check not to be reported, got %d results", reported)
+	}
+	if results, _ := storage.ListCheckResults(d.ID, time.Time{}, time.Time{}, 1, 10); len(results) != 0 {
+		t.Errorf("Expected canceled check not to be saved, got %d results", len(results))
+	}
+}
+
+// TestSchedulerStart testa os erros de Start
+func TestSchedulerStart(t *testing.T) {
+	t.Run("Already Running", func(t *testing.T) {
+		s := newTestScheduler(t, helpers.NewMockStorage(), newMockChecker(), nil)
+
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Start(context.Background()); !errors.Is(err, ErrAlreadyRunning) {
+			t.Errorf("Expected ErrAlreadyRunning, got %v", err)
+		}
+
+		// Após Stop, pode ser iniciado novamente
+		s.Stop()
+		if err := s.Start(context.Background()); err != nil {
+			t.Errorf("Expected restart to succeed, got %v", err)
+		}
+	})
+
+	t.Run("Restart After Context Canceled", func(t *testing.T) {
+		storage := helpers.NewMockStorage()
+		d := helpers.NewTestDomainBuilder().Build()
+		_, _ = storage.CreateDomain(d)
+
+		checker := newMockChecker()
+		s := newTestScheduler(t, storage, checker, &Config{DefaultInterval: 10 * time.Millisecond})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := s.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+
+		// O fim da execução libera o scheduler sem precisar de Stop
+		var err error
+		waitFor(t, func() bool {
+			err = s.Start(context.Background())
+			return !errors.Is(err, ErrAlreadyRunning)
+		}, "Expected scheduler to accept Start after its context was canceled")
+		if err != nil {
+			t.Fatalf("Expected restart to succeed, got %v", err)
+		}
+
+		checks := checker.count(d.ID)
+		waitFor(t, func() bool { return checker.count(d.ID) > checks }, "Expected restarted scheduler to run checks")
+	})
+
+	t.Run("Domain Deleted While Stopped", func(t *testing.T) {
+		storage := helpers.NewMockStorage()
+		kept := helpers.NewTestDomainBuilder().Build()
+		deleted := helpers.NewTestDomainBuilder().Build()
+		_, _ = storage.CreateDomain(kept)
+		_, _ = storage.CreateDomain(deleted)
+
+		checker := newMockChecker()
+		s := newTestScheduler(t, storage, checker, &Config{DefaultInterval: 10 * time.Millisecond})
+
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		s.Stop()
+
+		if err := storage.DeleteDomain(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		s.mu.Lock()
+		_, stale := s.entries[deleted.ID]
+		_, ok := s.entries[kept.ID]
+		scheduled := len(s.entries)
+		s.mu.Unlock()
+		if stale || !ok {
+			t.Errorf("Expected only the remaining domain to be scheduled, got %d entries", scheduled)
+		}
+
+		checks := checker.count(deleted.ID)
+		waitFor(t, func() bool { return checker.count(kept.ID) >= 3 }, "Expected remaining domain to be checked")
+		if checker.count(deleted.ID) != checks {
+			t.Error("Expected deleted domain not to be checked after restart")
+		}
+	})
+
+	t.Run("Storage Error", func(t *testing.T) {
+		storage := helpers.NewMockStorage()
+		storage.SetListDomainsError(true)
+		s := newTestScheduler(t, storage, newMockChecker(), nil)
+
+		if err := s.Start(context.Background()); err == nil {
+			t.Error("Expected storage error, got nil")
+		}
+	})
+}
+
+// TestSchedulerIntervals testa o intervalo por domínio e a variação aplicada
+func TestSchedulerIntervals(t *testing.T) {
+	s := NewSchedulerWithConfig(helpers.NewMockStorage(), newMockChecker(), &Config{
+		DefaultInterval: 30 * time.Second,
+		Jitter:          0.2,
+	}).(*scheduler)
+
+	if got := s.interval(&models.Domain{}); got != 30*time.Second {
+		t.Errorf("Expected default interval 30s, got %v", got)
+	}
+	if got := s.interval(&models.Domain{Interval: 300}); got != 5*time.Minute {
+		t.Errorf("Expected domain interval 5m, got %v", got)
+	}
+
+	tests := []struct {
+		random   float64
+		expected time.Duration
+	}{
+		{0, 80 * time.Second},
+		{0.5, 100 * time.Second},
+		{1, 120 * time.Second},
+	}
+
+	for _, tt := range tests {
+		s.random = func() float64 { return tt.random }
+		if got := s.jittered(100 * time.Second); got != tt.expected {
+			t.Errorf("random %v: expected %v, got %v", tt.random, tt.expected, got)
+		}
+	}
+
+	s.random = func() float64 { return 0.5 }
+	if got := s.spread(100*time.Second, 1); got != 50*time.Second {
+		t.Errorf("Expected spread 50s, got %v", got)
+	}
+}
@@ -1,8 +1,15 @@
 package storage
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/luizhreis/domain-watcher/internal/storage/errs"
+)
 
 var (
 	ErrUnsupportedStorageType = errors.New("unsupported storage type")
-	ErrDomainNotFound         = errors.New("domain not found")
+	ErrDomainNotFound         = errs.ErrDomainNotFound
+	ErrCheckResultNotFound    = errs.ErrCheckResultNotFound
+	ErrDNSChangeNotFound      = errs.ErrDNSChangeNotFound
+	ErrDomainAlreadyExists    = errs.ErrDomainAlreadyExists
 )
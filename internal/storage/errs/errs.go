@@ -0,0 +1,13 @@
+// Package errs define os erros compartilhados pelas implementações de storage.
+// Fica separado do pacote storage para que os backends o importem sem ciclo,
+// já que storage importa os backends em NewStorage.
+package errs
+
+import "errors"
+
+var (
+	ErrDomainNotFound      = errors.New("domain not found")
+	ErrCheckResultNotFound = errors.New("check result not found")
+	ErrDNSChangeNotFound   = errors.New("dns change not found")
+	ErrDomainAlreadyExists = errors.New("domain already exists")
+)
@@ -2,12 +2,15 @@ package storage
 
 import (
 	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/internal/storage/postgres"
+	"github.com/luizhreis/domain-watcher/internal/storage/sqlite"
 )
 
 type StorageType string
 
 const (
 	StorageTypeMemory     StorageType = "memory"
+	StorageTypeSQLite     StorageType = "sqlite"
 	StorageTypePostgreSQL StorageType = "postgresql"
 )
 
@@ -16,18 +19,32 @@ type StorageConfig struct {
 	Type    StorageType            `json:"type"`
 	Path    string                 `json:"path,omitempty"`    // Para SQLite: caminho do arquivo
 	DSN     string                 `json:"dsn,omitempty"`     // Para PostgreSQL: string de conexão
-	Options map[string]interface{} `json:"options,omitempty"` // Configurações específicas
+	Options map[string]interface{} `json:"options,omitempty"` // Para PostgreSQL: opções do pool (postgres.Option*)
 }
 
-var _ Storage = (*memory.MemoryStorage)(nil)
+var (
+	_ Storage = (*memory.MemoryStorage)(nil)
+	_ Storage = (*sqlite.SQLiteStorage)(nil)
+	_ Storage = (*postgres.PostgresStorage)(nil)
+)
 
 // NewStorage creates a new storage instance based on the provided type.
 func NewStorage(config *StorageConfig) (Storage, error) {
 	switch config.Type {
 	case StorageTypeMemory:
 		return memory.NewMemoryStorage(), nil
+	case StorageTypeSQLite:
+		s, err := sqlite.NewSQLiteStorage(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
 	case StorageTypePostgreSQL:
-		return nil, ErrUnsupportedStorageType
+		s, err := postgres.NewPostgresStorageWithOptions(config.DSN, config.Options)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
 	default:
 		return nil, ErrUnsupportedStorageType
 	}
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/models"
 )
@@ -8,7 +11,48 @@ import (
 type Storage interface {
 	CreateDomain(domain *models.Domain) (uuid.UUID, error)
 	GetDomain(id uuid.UUID) (*models.Domain, error)
-	ListDomains(page, pageSize int) ([]*models.Domain, error)
+	// ListDomains retorna uma página de domínios na ordem de opts (por padrão,
+	// CreatedAt e depois ID) e o total de domínios
+	ListDomains(opts models.ListOptions) (*models.DomainPage, error)
+	// ListDomainsCursor pagina por cursor, na mesma ordem de ListDomains. É
+	// estável mesmo com domínios criados entre as requisições.
+	ListDomainsCursor(opts models.CursorOptions) (*models.DomainCursorPage, error)
 	UpdateDomain(domain *models.Domain) error
 	DeleteDomain(id uuid.UUID) error
+
+	// Histórico de mudanças de IP, em ordem cronológica
+	SaveDNSChange(event *models.DNSChangeEvent) error
+	ListDNSChanges(domainID uuid.UUID) ([]*models.DNSChangeEvent, error)
+	// LatestDNSChange retorna a última mudança do histórico, sem ler o restante
+	LatestDNSChange(domainID uuid.UUID) (*models.DNSChangeEvent, error)
+
+	// Histórico de verificações. ListCheckResults retorna os resultados com
+	// from <= CheckedAt < to, do mais recente ao mais antigo; limites zerados
+	// não restringem o intervalo.
+	SaveCheckResult(result *models.CheckResult) error
+	ListCheckResults(domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error)
+	// ListCheckResultsCursor pagina por cursor no mesmo intervalo, do mais
+	// recente ao mais antigo, desempatando pelo ID. cursor vazio começa do início.
+	ListCheckResultsCursor(domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error)
+	LatestCheckResult(domainID uuid.UUID) (*models.CheckResult, error)
+
+	// Variantes com Context dos métodos acima. A operação é abandonada quando
+	// ctx é cancelado ou seu prazo expira, retornando o erro de ctx; as
+	// versões sem Context equivalem a usar context.Background.
+	CreateDomainContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error)
+	GetDomainContext(ctx context.Context, id uuid.UUID) (*models.Domain, error)
+	ListDomainsContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error)
+	ListDomainsCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error)
+	UpdateDomainContext(ctx context.Context, domain *models.Domain) error
+	DeleteDomainContext(ctx context.Context, id uuid.UUID) error
+	SaveDNSChangeContext(ctx context.Context, event *models.DNSChangeEvent) error
+	ListDNSChangesContext(ctx context.Context, domainID uuid.UUID) ([]*models.DNSChangeEvent, error)
+	LatestDNSChangeContext(ctx context.Context, domainID uuid.UUID) (*models.DNSChangeEvent, error)
+	SaveCheckResultContext(ctx context.Context, result *models.CheckResult) error
+	ListCheckResultsContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error)
+	ListCheckResultsCursorContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error)
+	LatestCheckResultContext(ctx context.Context, domainID uuid.UUID) (*models.CheckResult, error)
+
+	// Close libera os recursos do storage, como conexões com o banco
+	Close() error
 }
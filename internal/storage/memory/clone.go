@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// As funções abaixo fazem cópias profundas dos modelos. O storage guarda e
+// devolve apenas cópias, para que quem chama não altere o estado interno.
+
+func cloneDomain(domain *models.Domain) *models.Domain {
+	clone := *domain
+	clone.Tags = slices.Clone(domain.Tags)
+
+	if domain.Assertions != nil {
+		assertions := *domain.Assertions
+		assertions.StatusCodes = slices.Clone(domain.Assertions.StatusCodes)
+		assertions.BodyContains = slices.Clone(domain.Assertions.BodyContains)
+		assertions.BodyNotContains = slices.Clone(domain.Assertions.BodyNotContains)
+		assertions.BodyMatches = slices.Clone(domain.Assertions.BodyMatches)
+		assertions.BodyNotMatches = slices.Clone(domain.Assertions.BodyNotMatches)
+		assertions.JSON = slices.Clone(domain.Assertions.JSON)
+		assertions.Headers = maps.Clone(domain.Assertions.Headers)
+		clone.Assertions = &assertions
+	}
+
+	return &clone
+}
+
+func cloneDNSChange(event *models.DNSChangeEvent) *models.DNSChangeEvent {
+	clone := *event
+	clone.PreviousIPs = slices.Clone(event.PreviousIPs)
+	clone.CurrentIPs = slices.Clone(event.CurrentIPs)
+	clone.ExpectedIPs = slices.Clone(event.ExpectedIPs)
+	return &clone
+}
+
+func cloneCheckResult(result *models.CheckResult) *models.CheckResult {
+	clone := *result
+	clone.ResolvedIPs = slices.Clone(result.ResolvedIPs)
+	clone.AssertionFailures = slices.Clone(result.AssertionFailures)
+
+	if result.RedirectChain != nil {
+		chain := *result.RedirectChain
+		chain.Hops = slices.Clone(result.RedirectChain.Hops)
+		clone.RedirectChain = &chain
+	}
+
+	if result.TLS != nil {
+		tls := *result.TLS
+		tls.SANs = slices.Clone(result.TLS.SANs)
+		clone.TLS = &tls
+	}
+
+	if result.Timings != nil {
+		timings := *result.Timings
+		clone.Timings = &timings
+	}
+
+	return &clone
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+)
+
+// As operações em memória não bloqueiam; as variantes com Context apenas
+// recusam começar quando ctx já terminou.
+
+func (m *MemoryStorage) CreateDomainContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return uuid.Nil, err
+	}
+	return m.CreateDomain(domain)
+}
+
+func (m *MemoryStorage) GetDomainContext(ctx context.Context, id uuid.UUID) (*models.Domain, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetDomain(id)
+}
+
+func (m *MemoryStorage) ListDomainsContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDomains(opts)
+}
+
+func (m *MemoryStorage) ListDomainsCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDomainsCursor(opts)
+}
+
+func (m *MemoryStorage) UpdateDomainContext(ctx context.Context, domain *models.Domain) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.UpdateDomain(domain)
+}
+
+func (m *MemoryStorage) DeleteDomainContext(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.DeleteDomain(id)
+}
+
+func (m *MemoryStorage) SaveDNSChangeContext(ctx context.Context, event *models.DNSChangeEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.SaveDNSChange(event)
+}
+
+func (m *MemoryStorage) ListDNSChangesContext(ctx context.Context, domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDNSChanges(domainID)
+}
+
+func (m *MemoryStorage) LatestDNSChangeContext(ctx context.Context, domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LatestDNSChange(domainID)
+}
+
+func (m *MemoryStorage) SaveCheckResultContext(ctx context.Context, result *models.CheckResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.SaveCheckResult(result)
+}
+
+func (m *MemoryStorage) ListCheckResultsContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListCheckResults(domainID, from, to, page, pageSize)
+}
+
+func (m *MemoryStorage) ListCheckResultsCursorContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListCheckResultsCursor(domainID, from, to, cursor, limit)
+}
+
+func (m *MemoryStorage) LatestCheckResultContext(ctx context.Context, domainID uuid.UUID) (*models.CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LatestCheckResult(domainID)
+}
@@ -2,31 +2,58 @@ package memory
 
 import (
 	"errors"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/errs"
 )
 
 var (
 	// ErrDomainNotFound é retornado quando um domínio não é encontrado
-	ErrDomainNotFound = errors.New("domain not found")
+	ErrDomainNotFound = errs.ErrDomainNotFound
+	// ErrCheckResultNotFound é retornado quando o domínio ainda não tem resultados
+	ErrCheckResultNotFound = errs.ErrCheckResultNotFound
+	// ErrDNSChangeNotFound é retornado quando o domínio ainda não tem mudanças de IP
+	ErrDNSChangeNotFound = errs.ErrDNSChangeNotFound
+	// ErrDomainAlreadyExists é retornado quando outro domínio já usa a mesma URL
+	ErrDomainAlreadyExists = errs.ErrDomainAlreadyExists
 )
 
-// MemoryStorage é uma implementação in-memory do Storage
+// MemoryStorage é uma implementação in-memory do Storage, segura para uso
+// concorrente. Os modelos são copiados na gravação e na leitura.
 type MemoryStorage struct {
+	mu      sync.RWMutex
 	domains map[uuid.UUID]*models.Domain
+	// urls indexa os domínios pela URL, que é única
+	urls       map[string]uuid.UUID
+	dnsChanges map[uuid.UUID][]*models.DNSChangeEvent
+	// checkResults guarda os resultados de cada domínio ordenados por CheckedAt e ID
+	checkResults map[uuid.UUID][]*models.CheckResult
 }
 
 // NewMemoryStorage cria uma nova instância de MemoryStorage
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		domains: make(map[uuid.UUID]*models.Domain),
+		domains:      make(map[uuid.UUID]*models.Domain),
+		urls:         make(map[string]uuid.UUID),
+		dnsChanges:   make(map[uuid.UUID][]*models.DNSChangeEvent),
+		checkResults: make(map[uuid.UUID][]*models.CheckResult),
 	}
 }
 
 // CreateDomain cria um novo domínio no storage
 func (m *MemoryStorage) CreateDomain(domain *models.Domain) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.urls[domain.URL]; exists {
+		return uuid.Nil, ErrDomainAlreadyExists
+	}
+
 	domain.ID = uuid.New()
 
 	// Define timestamps
@@ -34,76 +61,348 @@ func (m *MemoryStorage) CreateDomain(domain *models.Domain) (uuid.UUID, error) {
 	domain.CreatedAt = now
 	domain.UpdatedAt = now
 
-	// Armazena no map
-	m.domains[domain.ID] = domain
+	// O status vem das verificações gravadas depois
+	domain.Status = models.StatusUnknown
+	domain.LastCheckedAt = time.Time{}
+
+	// Armazena uma cópia no map
+	m.domains[domain.ID] = cloneDomain(domain)
+	m.urls[domain.URL] = domain.ID
 
 	return domain.ID, nil
 }
 
+// Close não tem efeito no storage em memória
+func (m *MemoryStorage) Close() error {
+	return nil
+}
+
 // GetDomain busca um domínio pelo ID
 func (m *MemoryStorage) GetDomain(id uuid.UUID) (*models.Domain, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	domain, exists := m.domains[id]
 	if !exists {
 		return nil, ErrDomainNotFound
 	}
-	return domain, nil
+	return cloneDomain(domain), nil
 }
 
 // GetAllDomains retorna todos os domínios
 func (m *MemoryStorage) GetAllDomains() ([]*models.Domain, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	domains := make([]*models.Domain, 0, len(m.domains))
 	for _, domain := range m.domains {
-		domains = append(domains, domain)
+		domains = append(domains, cloneDomain(domain))
 	}
 	return domains, nil
 }
 
-// ListDomains retorna domínios com paginação
-func (m *MemoryStorage) ListDomains(page, pageSize int) ([]*models.Domain, error) {
-	if page < 1 || pageSize < 1 {
-		return nil, errors.New("page and pageSize must be greater than 0")
+// ListDomains retorna uma página de domínios na ordem definida por opts
+func (m *MemoryStorage) ListDomains(opts models.ListOptions) (*models.DomainPage, error) {
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Converte map para slice para permitir paginação
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Converte map para slice e ordena para que a paginação seja estável
+	now := time.Now()
 	allDomains := make([]*models.Domain, 0, len(m.domains))
 	for _, domain := range m.domains {
-		allDomains = append(allDomains, domain)
+		if opts.Filter.Matches(domain, now) {
+			allDomains = append(allDomains, domain)
+		}
 	}
+	slices.SortFunc(allDomains, opts.Compare)
 
 	// Calcula offset
-	startIndex := (page - 1) * pageSize
-	endIndex := startIndex + pageSize
+	startIndex := min(opts.Offset(), len(allDomains))
+	endIndex := min(startIndex+opts.PageSize, len(allDomains))
+
+	domains := make([]*models.Domain, 0, endIndex-startIndex)
+	for _, domain := range allDomains[startIndex:endIndex] {
+		domains = append(domains, cloneDomain(domain))
+	}
+
+	return &models.DomainPage{
+		Domains:  domains,
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+		Total:    len(allDomains),
+	}, nil
+}
+
+// ListDomainsCursor retorna os domínios após opts.Cursor na ordem definida por opts
+func (m *MemoryStorage) ListDomainsCursor(opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	listOpts, cursor, err := opts.Decode()
+	if err != nil {
+		return nil, err
+	}
 
-	// Verifica limites
-	if startIndex >= len(allDomains) {
-		return []*models.Domain{}, nil // Página vazia, mas não é erro
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var after *models.Domain
+	if cursor != nil {
+		after = cursor.Domain()
 	}
 
-	if endIndex > len(allDomains) {
-		endIndex = len(allDomains)
+	now := time.Now()
+	matched := make([]*models.Domain, 0, len(m.domains))
+	for _, domain := range m.domains {
+		if !listOpts.Filter.Matches(domain, now) {
+			continue
+		}
+		if after == nil || listOpts.Compare(after, domain) < 0 {
+			matched = append(matched, domain)
+		}
 	}
+	slices.SortFunc(matched, listOpts.Compare)
 
-	return allDomains[startIndex:endIndex], nil
+	// Um domínio além do limite indica que há próxima página
+	matched = matched[:min(len(matched), opts.Limit+1)]
+	domains := make([]*models.Domain, 0, len(matched))
+	for _, domain := range matched {
+		domains = append(domains, cloneDomain(domain))
+	}
+
+	return models.NewDomainCursorPage(domains, listOpts, opts.Limit), nil
 }
 
 // UpdateDomain atualiza um domínio existente
 func (m *MemoryStorage) UpdateDomain(domain *models.Domain) error {
-	if _, exists := m.domains[domain.ID]; !exists {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, exists := m.domains[domain.ID]
+	if !exists {
 		return ErrDomainNotFound
 	}
+	if id, exists := m.urls[domain.URL]; exists && id != domain.ID {
+		return ErrDomainAlreadyExists
+	}
 
 	domain.UpdatedAt = time.Now()
-	m.domains[domain.ID] = domain
+
+	// CreatedAt, Status e LastCheckedAt são mantidos pelo storage
+	updated := cloneDomain(domain)
+	updated.CreatedAt = stored.CreatedAt
+	updated.Status = stored.Status
+	updated.LastCheckedAt = stored.LastCheckedAt
+	m.domains[domain.ID] = updated
+
+	delete(m.urls, stored.URL)
+	m.urls[domain.URL] = domain.ID
 
 	return nil
 }
 
 // DeleteDomain remove um domínio
 func (m *MemoryStorage) DeleteDomain(id uuid.UUID) error {
-	if _, exists := m.domains[id]; !exists {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	domain, exists := m.domains[id]
+	if !exists {
 		return ErrDomainNotFound
 	}
 
+	delete(m.urls, domain.URL)
 	delete(m.domains, id)
+	delete(m.dnsChanges, id)
+	delete(m.checkResults, id)
+	return nil
+}
+
+// SaveDNSChange registra uma mudança de IP no histórico do domínio
+func (m *MemoryStorage) SaveDNSChange(event *models.DNSChangeEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.domains[event.DomainID]; !exists {
+		return ErrDomainNotFound
+	}
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	m.dnsChanges[event.DomainID] = append(m.dnsChanges[event.DomainID], cloneDNSChange(event))
+	return nil
+}
+
+// ListDNSChanges retorna o histórico de mudanças de IP do domínio, do mais antigo ao mais recente
+func (m *MemoryStorage) ListDNSChanges(domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, ErrDomainNotFound
+	}
+
+	events := make([]*models.DNSChangeEvent, 0, len(m.dnsChanges[domainID]))
+	for _, event := range m.dnsChanges[domainID] {
+		events = append(events, cloneDNSChange(event))
+	}
+	return events, nil
+}
+
+// LatestDNSChange retorna a mudança de IP mais recente do domínio
+func (m *MemoryStorage) LatestDNSChange(domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, ErrDomainNotFound
+	}
+
+	events := m.dnsChanges[domainID]
+	if len(events) == 0 {
+		return nil, ErrDNSChangeNotFound
+	}
+	return cloneDNSChange(events[len(events)-1]), nil
+}
+
+// SaveCheckResult registra o resultado de uma verificação do domínio
+func (m *MemoryStorage) SaveCheckResult(result *models.CheckResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	domain, exists := m.domains[result.DomainID]
+	if !exists {
+		return ErrDomainNotFound
+	}
+
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+	}
+	if result.CheckedAt.IsZero() {
+		result.CheckedAt = time.Now()
+	}
+
+	// Mantém a ordem por CheckedAt e ID mesmo com resultados gravados fora de ordem
+	results := m.checkResults[result.DomainID]
+	i := sort.Search(len(results), func(i int) bool {
+		return models.CompareCheckResults(results[i], result) > 0
+	})
+	results = append(results, nil)
+	copy(results[i+1:], results[i:])
+	results[i] = cloneCheckResult(result)
+	m.checkResults[result.DomainID] = results
+
+	// O status do domínio acompanha o resultado mais recente
+	latest := results[len(results)-1]
+	domain.Status = latest.Status()
+	domain.LastCheckedAt = latest.CheckedAt
+
 	return nil
 }
+
+// ListCheckResults retorna os resultados do domínio no intervalo [from, to),
+// do mais recente ao mais antigo, com paginação
+func (m *MemoryStorage) ListCheckResults(domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("page and pageSize must be greater than 0")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, ErrDomainNotFound
+	}
+
+	results := m.checkResults[domainID]
+	matched := make([]*models.CheckResult, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		checkedAt := results[i].CheckedAt
+		if !from.IsZero() && checkedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !checkedAt.Before(to) {
+			continue
+		}
+		matched = append(matched, results[i])
+	}
+
+	// Calcula offset
+	startIndex := (page - 1) * pageSize
+	endIndex := startIndex + pageSize
+
+	if startIndex >= len(matched) {
+		return []*models.CheckResult{}, nil
+	}
+
+	if endIndex > len(matched) {
+		endIndex = len(matched)
+	}
+
+	paged := make([]*models.CheckResult, 0, endIndex-startIndex)
+	for _, result := range matched[startIndex:endIndex] {
+		paged = append(paged, cloneCheckResult(result))
+	}
+	return paged, nil
+}
+
+// ListCheckResultsCursor retorna os resultados do domínio no intervalo
+// [from, to) após cursor, do mais recente ao mais antigo
+func (m *MemoryStorage) ListCheckResultsCursor(domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	if limit < 1 {
+		return nil, models.ErrInvalidLimit
+	}
+
+	var after *models.CheckResultCursor
+	if cursor != "" {
+		var err error
+		if after, err = models.DecodeCheckResultCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, ErrDomainNotFound
+	}
+
+	results := m.checkResults[domainID]
+	matched := make([]*models.CheckResult, 0, limit+1)
+	for i := len(results) - 1; i >= 0 && len(matched) <= limit; i-- {
+		result := results[i]
+		if after != nil && !after.Follows(result) {
+			continue
+		}
+		if !from.IsZero() && result.CheckedAt.Before(from) {
+			break
+		}
+		if !to.IsZero() && !result.CheckedAt.Before(to) {
+			continue
+		}
+		matched = append(matched, cloneCheckResult(result))
+	}
+
+	return models.NewCheckResultCursorPage(matched, limit), nil
+}
+
+// LatestCheckResult retorna o resultado mais recente do domínio
+func (m *MemoryStorage) LatestCheckResult(domainID uuid.UUID) (*models.CheckResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, ErrDomainNotFound
+	}
+
+	results := m.checkResults[domainID]
+	if len(results) == 0 {
+		return nil, ErrCheckResultNotFound
+	}
+	return cloneCheckResult(results[len(results)-1]), nil
+}
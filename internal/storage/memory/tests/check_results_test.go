@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// newStorageWithDomain cria um storage com um domínio cadastrado
+func newStorageWithDomain(t *testing.T) (*memory.MemoryStorage, uuid.UUID) {
+	t.Helper()
+
+	storage := memory.NewMemoryStorage()
+	id, err := storage.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage, id
+}
+
+// saveResults grava um resultado por horário informado, fora de ordem de propósito
+func saveResults(t *testing.T, storage *memory.MemoryStorage, domainID uuid.UUID, times ...time.Time) {
+	t.Helper()
+
+	for _, checkedAt := range times {
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(domainID).Build()
+		result.CheckedAt = checkedAt
+		if err := storage.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSaveCheckResult(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+
+	t.Run("Assigns ID And Timestamp", func(t *testing.T) {
+		result := &models.CheckResult{DomainID: domainID, StatusCode: 200}
+		if err := storage.SaveCheckResult(result); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.ID == uuid.Nil {
+			t.Error("Expected ID to be assigned")
+		}
+		if result.CheckedAt.IsZero() {
+			t.Error("Expected CheckedAt to be assigned")
+		}
+	})
+
+	t.Run("Unknown Domain", func(t *testing.T) {
+		result := helpers.NewTestCheckResultBuilder().Build()
+		if err := storage.SaveCheckResult(result); !errors.Is(err, memory.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Deleted With Domain", func(t *testing.T) {
+		if err := storage.DeleteDomain(domainID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := storage.LatestCheckResult(domainID); !errors.Is(err, memory.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound after delete, got %v", err)
+		}
+	})
+}
+
+func TestListCheckResults(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	saveResults(t, storage, domainID,
+		base.Add(2*time.Minute), base, base.Add(4*time.Minute), base.Add(time.Minute), base.Add(3*time.Minute))
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		page     int
+		pageSize int
+		expected []time.Duration
+	}{
+		{"All Newest First", time.Time{}, time.Time{}, 1, 10, []time.Duration{4, 3, 2, 1, 0}},
+		{"Second Page", time.Time{}, time.Time{}, 2, 2, []time.Duration{2, 1}},
+		{"Past Last Page", time.Time{}, time.Time{}, 4, 2, nil},
+		{"From Inclusive", base.Add(3 * time.Minute), time.Time{}, 1, 10, []time.Duration{4, 3}},
+		{"To Exclusive", time.Time{}, base.Add(2 * time.Minute), 1, 10, []time.Duration{1, 0}},
+		{"Range Paginated", base.Add(time.Minute), base.Add(4 * time.Minute), 2, 2, []time.Duration{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := storage.ListCheckResults(domainID, tt.from, tt.to, tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(results) != len(tt.expected) {
+				t.Fatalf("Expected %d results, got %d", len(tt.expected), len(results))
+			}
+			for i, offset := range tt.expected {
+				if expected := base.Add(offset * time.Minute); !results[i].CheckedAt.Equal(expected) {
+					t.Errorf("Result %d: expected %v, got %v", i, expected, results[i].CheckedAt)
+				}
+			}
+		})
+	}
+
+	t.Run("Invalid Pagination", func(t *testing.T) {
+		if _, err := storage.ListCheckResults(domainID, time.Time{}, time.Time{}, 0, 10); err == nil {
+			t.Error("Expected error for invalid page, got nil")
+		}
+	})
+
+	t.Run("Unknown Domain", func(t *testing.T) {
+		_, err := storage.ListCheckResults(uuid.New(), time.Time{}, time.Time{}, 1, 10)
+		if !errors.Is(err, memory.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound, got %v", err)
+		}
+	})
+}
+
+func TestLatestCheckResult(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := storage.LatestCheckResult(domainID); !errors.Is(err, memory.ErrCheckResultNotFound) {
+		t.Errorf("Expected ErrCheckResultNotFound, got %v", err)
+	}
+
+	saveResults(t, storage, domainID, base.Add(time.Minute), base.Add(5*time.Minute), base)
+
+	latest, err := storage.LatestCheckResult(domainID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !latest.CheckedAt.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("Expected latest result at %v, got %v", base.Add(5*time.Minute), latest.CheckedAt)
+	}
+
+	if _, err := storage.LatestCheckResult(uuid.New()); !errors.Is(err, memory.ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestConcurrentAccess exercita todas as operações em paralelo. Deve ser
+// executado com -race para detectar acessos sem sincronização.
+func TestConcurrentAccess(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+
+	const workers = 8
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range iterations {
+				domain := helpers.NewTestDomainBuilder().Build()
+				id, err := storage.CreateDomain(domain)
+				if err != nil {
+					t.Errorf("CreateDomain: %v", err)
+					return
+				}
+
+				result := helpers.NewTestCheckResultBuilder().WithDomainID(id).Build()
+				if err := storage.SaveCheckResult(result); err != nil {
+					t.Errorf("SaveCheckResult: %v", err)
+				}
+				event := &models.DNSChangeEvent{DomainID: id, CurrentIPs: []string{"192.0.2.1"}, DetectedAt: time.Now()}
+				if err := storage.SaveDNSChange(event); err != nil {
+					t.Errorf("SaveDNSChange: %v", err)
+				}
+
+				domain.Name = "updated"
+				if err := storage.UpdateDomain(domain); err != nil {
+					t.Errorf("UpdateDomain: %v", err)
+				}
+
+				_, _ = storage.GetDomain(id)
+				_, _ = storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10})
+				_, _ = storage.ListCheckResults(id, time.Time{}, time.Time{}, 1, 10)
+				_, _ = storage.LatestCheckResult(id)
+				_, _ = storage.ListDNSChanges(id)
+
+				// Metade dos domínios é removida enquanto os outros workers leem
+				if i%2 == 0 {
+					if err := storage.DeleteDomain(id); err != nil {
+						t.Errorf("DeleteDomain: %v", err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	page, err := storage.ListDomains(models.ListOptions{Page: 1, PageSize: workers * iterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := workers * iterations / 2; len(page.Domains) != expected || page.Total != expected {
+		t.Errorf("Expected %d domains, got %d (total %d)", expected, len(page.Domains), page.Total)
+	}
+}
+
+// TestReturnsCopies testa que alterar os valores gravados ou retornados não
+// altera o estado interno do storage
+func TestReturnsCopies(t *testing.T) {
+	t.Run("Domain", func(t *testing.T) {
+		storage := memory.NewMemoryStorage()
+		domain := helpers.NewTestDomainBuilder().WithURL("example.com").
+			WithAssertions(&models.Assertions{BodyContains: []string{"ok"}, Headers: map[string]string{"X-Service": "api"}}).
+			Build()
+		id, _ := storage.CreateDomain(domain)
+
+		// O valor passado a CreateDomain não é guardado
+		domain.URL = "changed-after-create.com"
+		domain.Assertions.BodyContains[0] = "changed"
+
+		got, err := storage.GetDomain(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got.URL = "changed-after-get.com"
+		got.Assertions.Headers["X-Service"] = "changed"
+
+		listed, _ := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10})
+		listed.Domains[0].URL = "changed-after-list.com"
+
+		stored, _ := storage.GetDomain(id)
+		if stored.URL != "example.com" {
+			t.Errorf("Expected stored URL to be unchanged, got %q", stored.URL)
+		}
+		if stored.Assertions.BodyContains[0] != "ok" || stored.Assertions.Headers["X-Service"] != "api" {
+			t.Errorf("Expected stored assertions to be unchanged, got %+v", stored.Assertions)
+		}
+	})
+
+	t.Run("Check Result", func(t *testing.T) {
+		storage, domainID := newStorageWithDomain(t)
+		result := &models.CheckResult{
+			DomainID:          domainID,
+			ResolvedIPs:       []string{"192.0.2.1"},
+			RedirectChain:     &models.RedirectChain{Hops: []models.RedirectHop{{URL: "http://example.com/"}}},
+			TLS:               &models.TLSInfo{SANs: []string{"example.com"}},
+			Timings:           &models.Timings{DNSLookup: 3},
+			AssertionFailures: []models.AssertionFailure{{Assertion: models.AssertionStatusCodes}},
+		}
+		if err := storage.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+		result.ResolvedIPs[0] = "198.51.100.1"
+
+		latest, _ := storage.LatestCheckResult(domainID)
+		latest.RedirectChain.Hops[0].URL = "http://changed.example.com/"
+		latest.TLS.SANs[0] = "changed.example.com"
+		latest.Timings.DNSLookup = 100
+		latest.AssertionFailures[0].Actual = "changed"
+
+		listed, _ := storage.ListCheckResults(domainID, time.Time{}, time.Time{}, 1, 10)
+		listed[0].StatusCode = 500
+
+		stored, _ := storage.LatestCheckResult(domainID)
+		if stored.ResolvedIPs[0] != "192.0.2.1" || stored.StatusCode != 0 ||
+			stored.RedirectChain.Hops[0].URL != "http://example.com/" || stored.TLS.SANs[0] != "example.com" ||
+			stored.Timings.DNSLookup != 3 || stored.AssertionFailures[0].Actual != "" {
+			t.Errorf("Expected stored result to be unchanged, got %+v", stored)
+		}
+	})
+
+	t.Run("DNS Change", func(t *testing.T) {
+		storage, domainID := newStorageWithDomain(t)
+		event := &models.DNSChangeEvent{DomainID: domainID, CurrentIPs: []string{"192.0.2.1"}}
+		if err := storage.SaveDNSChange(event); err != nil {
+			t.Fatal(err)
+		}
+		event.CurrentIPs[0] = "198.51.100.1"
+
+		history, _ := storage.ListDNSChanges(domainID)
+		history[0].CurrentIPs[0] = "203.0.113.1"
+
+		if stored, _ := storage.ListDNSChanges(domainID); stored[0].CurrentIPs[0] != "192.0.2.1" {
+			t.Errorf("Expected stored event to be unchanged, got %v", stored[0].CurrentIPs)
+		}
+	})
+
+	t.Run("Assigned Fields", func(t *testing.T) {
+		storage := memory.NewMemoryStorage()
+		domain := helpers.NewTestDomainBuilder().Build()
+		id, _ := storage.CreateDomain(domain)
+
+		// Os campos preenchidos pelo storage continuam visíveis para quem chama
+		if domain.ID != id || domain.CreatedAt.IsZero() || id == uuid.Nil {
+			t.Errorf("Expected ID and timestamps on the caller's value, got %+v", domain)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestContextCanceled testa que as variantes com Context não alteram nem
+// consultam o storage quando ctx já terminou
+func TestContextCanceled(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := storage.CreateDomainContext(ctx, helpers.NewTestDomainBuilder().Build()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on create, got %v", err)
+	}
+	if _, err := storage.GetDomainContext(ctx, domainID); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on get, got %v", err)
+	}
+
+	result := helpers.NewTestCheckResultBuilder().WithDomainID(domainID).Build()
+	if err := storage.SaveCheckResultContext(ctx, result); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on save, got %v", err)
+	}
+	if err := storage.DeleteDomainContext(ctx, domainID); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on delete, got %v", err)
+	}
+
+	page, err := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 {
+		t.Errorf("Expected only the original domain, got %d", page.Total)
+	}
+	if _, err := storage.LatestCheckResult(domainID); !errors.Is(err, memory.ErrCheckResultNotFound) {
+		t.Errorf("Expected no check results, got %v", err)
+	}
+
+	// Com um contexto válido, as variantes se comportam como os métodos comuns
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if d, err := storage.GetDomainContext(ctx, domainID); err != nil || d.ID != domainID {
+		t.Errorf("Expected domain %v, got %v (%v)", domainID, d, err)
+	}
+}
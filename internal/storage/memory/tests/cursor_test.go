@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestListDomainsCursor testa que a paginação por cursor percorre todos os
+// domínios uma única vez, mesmo com domínios criados entre as páginas
+func TestListDomainsCursor(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+
+	var ids []uuid.UUID
+	for range 5 {
+		id, err := storage.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	first, err := storage.ListDomainsCursor(models.CursorOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(first.Domains) != 2 || first.NextCursor == "" {
+		t.Fatalf("Expected 2 domains and a next cursor, got %d and %q", len(first.Domains), first.NextCursor)
+	}
+
+	// Um domínio novo vai para o fim da ordem de criação sem deslocar as páginas
+	created, _ := storage.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	ids = append(ids, created)
+
+	listed := []uuid.UUID{first.Domains[0].ID, first.Domains[1].ID}
+	cursor := first.NextCursor
+	for cursor != "" {
+		page, err := storage.ListDomainsCursor(models.CursorOptions{Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, d := range page.Domains {
+			listed = append(listed, d.ID)
+		}
+		cursor = page.NextCursor
+	}
+
+	if !slices.Equal(listed, ids) {
+		t.Errorf("Expected %v, got %v", ids, listed)
+	}
+}
+
+func TestListDomainsCursorSorted(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+	for _, name := range []string{"delta", "alpha", "charlie", "bravo", "alpha"} {
+		if _, err := storage.CreateDomain(helpers.NewTestDomainBuilder().WithName(name).Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := models.CursorOptions{Limit: 2, SortBy: models.SortByName, Order: models.SortDesc}
+
+	var names []string
+	for {
+		page, err := storage.ListDomainsCursor(opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, d := range page.Domains {
+			names = append(names, d.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	if expected := []string{"delta", "charlie", "bravo", "alpha", "alpha"}; !slices.Equal(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		first, _ := storage.ListDomainsCursor(models.CursorOptions{Limit: 1})
+
+		tests := []struct {
+			name     string
+			opts     models.CursorOptions
+			expected error
+		}{
+			{"Zero Limit", models.CursorOptions{}, models.ErrInvalidLimit},
+			{"Malformed Cursor", models.CursorOptions{Cursor: "not a cursor", Limit: 1}, models.ErrInvalidCursor},
+			{"Different Sort", models.CursorOptions{Cursor: first.NextCursor, Limit: 1, SortBy: models.SortByName}, models.ErrInvalidCursor},
+			{"Invalid Sort", models.CursorOptions{Limit: 1, SortBy: "ip"}, models.ErrInvalidSort},
+		}
+
+		for _, tt := range tests {
+			if _, err := storage.ListDomainsCursor(tt.opts); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+	})
+}
+
+// TestListCheckResultsCursor testa a paginação por cursor dos resultados,
+// inclusive com vários resultados no mesmo horário
+func TestListCheckResultsCursor(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	saveResults(t, storage, domainID,
+		base.Add(time.Minute), base, base.Add(2*time.Minute), base.Add(time.Minute), base.Add(time.Minute), base.Add(3*time.Minute))
+
+	expected, err := storage.ListCheckResults(domainID, base, base.Add(3*time.Minute), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed []*models.CheckResult
+	cursor := ""
+	for {
+		page, err := storage.ListCheckResultsCursor(domainID, base, base.Add(3*time.Minute), cursor, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		listed = append(listed, page.Results...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(listed) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(listed))
+	}
+	for i := range listed {
+		if listed[i].ID != expected[i].ID {
+			t.Errorf("Result %d: expected %v at %v, got %v at %v",
+				i, expected[i].ID, expected[i].CheckedAt, listed[i].ID, listed[i].CheckedAt)
+		}
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := storage.ListCheckResultsCursor(domainID, time.Time{}, time.Time{}, "", 0); !errors.Is(err, models.ErrInvalidLimit) {
+			t.Errorf("Expected ErrInvalidLimit, got %v", err)
+		}
+		if _, err := storage.ListCheckResultsCursor(domainID, time.Time{}, time.Time{}, "%%%", 1); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+		if _, err := storage.ListCheckResultsCursor(uuid.New(), time.Time{}, time.Time{}, "", 1); !errors.Is(err, memory.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound, got %v", err)
+		}
+	})
+}
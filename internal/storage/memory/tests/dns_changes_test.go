@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+)
+
+func TestLatestDNSChange(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+
+	if _, err := storage.LatestDNSChange(domainID); !errors.Is(err, memory.ErrDNSChangeNotFound) {
+		t.Errorf("Expected ErrDNSChangeNotFound, got %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, ip := range []string{"192.0.2.1", "192.0.2.2"} {
+		event := &models.DNSChangeEvent{DomainID: domainID, CurrentIPs: []string{ip}, DetectedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := storage.SaveDNSChange(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := storage.LatestDNSChange(domainID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if latest.CurrentIPs[0] != "192.0.2.2" {
+		t.Errorf("Expected the last change, got %+v", latest)
+	}
+
+	// O evento retornado é uma cópia
+	latest.CurrentIPs[0] = "changed"
+	if again, _ := storage.LatestDNSChange(domainID); again.CurrentIPs[0] != "192.0.2.2" {
+		t.Errorf("Expected stored change to be isolated from callers, got %+v", again)
+	}
+
+	if _, err := storage.LatestDNSChange(uuid.New()); !errors.Is(err, memory.ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound, got %v", err)
+	}
+}
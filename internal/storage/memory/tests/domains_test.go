@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestUpdateKeepsCreatedAt testa que UpdateDomain ignora o CreatedAt recebido
+// e mantém o gravado na criação, como os backends SQL
+func TestUpdateKeepsCreatedAt(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+
+	id, err := storage.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := storage.GetDomain(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := *created
+	changed.CreatedAt = created.CreatedAt.Add(-24 * time.Hour)
+	if err := storage.UpdateDomain(&changed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, _ := storage.GetDomain(id)
+	if !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Expected CreatedAt %v, got %v", created.CreatedAt, got.CreatedAt)
+	}
+}
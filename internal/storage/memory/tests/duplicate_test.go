@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestDuplicateURL testa que dois domínios não compartilham a mesma URL
+func TestDuplicateURL(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+
+	first := helpers.NewTestDomainBuilder().WithURL("https://example.com").Build()
+	if _, err := storage.CreateDomain(first); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicate := helpers.NewTestDomainBuilder().WithURL("https://example.com").Build()
+	if _, err := storage.CreateDomain(duplicate); !errors.Is(err, memory.ErrDomainAlreadyExists) {
+		t.Fatalf("Expected ErrDomainAlreadyExists on create, got %v", err)
+	}
+
+	second := helpers.NewTestDomainBuilder().WithURL("https://example.org").Build()
+	if _, err := storage.CreateDomain(second); err != nil {
+		t.Fatal(err)
+	}
+
+	second.URL = first.URL
+	if err := storage.UpdateDomain(second); !errors.Is(err, memory.ErrDomainAlreadyExists) {
+		t.Errorf("Expected ErrDomainAlreadyExists on update, got %v", err)
+	}
+
+	// Manter a própria URL não é conflito
+	first.Name = "renamed"
+	if err := storage.UpdateDomain(first); err != nil {
+		t.Errorf("Expected no error updating with the same URL, got %v", err)
+	}
+
+	// A URL fica livre quando o domínio troca de URL ou é removido
+	first.URL = "https://example.net"
+	if err := storage.UpdateDomain(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.DeleteDomain(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{"https://example.com", "https://example.net"} {
+		if _, err := storage.CreateDomain(helpers.NewTestDomainBuilder().WithURL(url).Build()); err != nil {
+			t.Errorf("Expected %s to be available, got %v", url, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// newFilterStorage cria quatro domínios: shop (up, verificado há 1 hora),
+// blog (down, verificado há 1 dia), shop-staging (nunca verificado) e api
+// (up, verificado agora)
+func newFilterStorage(t *testing.T) *memory.MemoryStorage {
+	t.Helper()
+
+	storage := memory.NewMemoryStorage()
+	now := time.Now()
+
+	domains := []struct {
+		builder   *helpers.TestDomainBuilder
+		status    int
+		checkedAt time.Time
+	}{
+		{helpers.NewTestDomainBuilder().WithName("shop").WithURL("https://Shop.example.com").WithTags("prod", "web"), 200, now.Add(-time.Hour)},
+		{helpers.NewTestDomainBuilder().WithName("blog").WithURL("https://blog.example.org").WithTags("prod"), 503, now.Add(-24 * time.Hour)},
+		{helpers.NewTestDomainBuilder().WithName("shop-staging").WithURL("https://staging.example.com").WithTags("staging"), 0, time.Time{}},
+		{helpers.NewTestDomainBuilder().WithName("api").WithURL("https://api.example.net"), 204, now},
+	}
+
+	for _, d := range domains {
+		id, err := storage.CreateDomain(d.builder.Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.checkedAt.IsZero() {
+			continue
+		}
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(id).WithStatusCode(d.status).WithCheckedAt(d.checkedAt).Build()
+		if err := storage.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return storage
+}
+
+func listNames(t *testing.T, storage *memory.MemoryStorage, opts models.ListOptions) []string {
+	t.Helper()
+
+	page, err := storage.ListDomains(opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if page.Total != len(page.Domains) {
+		t.Errorf("Expected total %d to match the filtered domains, got %d", len(page.Domains), page.Total)
+	}
+
+	var names []string
+	for _, d := range page.Domains {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func TestListDomainsFilter(t *testing.T) {
+	storage := newFilterStorage(t)
+
+	tests := []struct {
+		name     string
+		filter   models.DomainFilter
+		expected []string
+	}{
+		{"No Filter", models.DomainFilter{}, []string{"api", "blog", "shop", "shop-staging"}},
+		{"Search Name", models.DomainFilter{Search: "SHOP"}, []string{"shop", "shop-staging"}},
+		{"Search URL", models.DomainFilter{Search: "example.org"}, []string{"blog"}},
+		{"Tag", models.DomainFilter{Tag: "prod"}, []string{"blog", "shop"}},
+		{"Status Up", models.DomainFilter{Status: models.StatusUp}, []string{"api", "shop"}},
+		{"Status Down", models.DomainFilter{Status: models.StatusDown}, []string{"blog"}},
+		{"Status Unknown", models.DomainFilter{Status: models.StatusUnknown}, []string{"shop-staging"}},
+		{"Checked Recently", models.DomainFilter{MaxCheckAge: 2 * time.Hour}, []string{"api", "shop"}},
+		{"Stale Checks", models.DomainFilter{MinCheckAge: 30 * time.Minute}, []string{"blog", "shop"}},
+		{"Check Age Range", models.DomainFilter{MinCheckAge: 30 * time.Minute, MaxCheckAge: 2 * time.Hour}, []string{"shop"}},
+		{"Combined", models.DomainFilter{Search: "shop", Tag: "prod", Status: models.StatusUp}, []string{"shop"}},
+		{"No Match", models.DomainFilter{Tag: "missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := listNames(t, storage, models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName, Filter: tt.filter})
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+
+	t.Run("Created Range", func(t *testing.T) {
+		page, _ := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10})
+		second := page.Domains[1].CreatedAt
+		third := page.Domains[2].CreatedAt
+		if second.Equal(third) {
+			t.Skip("Domains created in the same clock tick")
+		}
+
+		filter := models.DomainFilter{CreatedAfter: second, CreatedBefore: third}
+		names := listNames(t, storage, models.ListOptions{Page: 1, PageSize: 10, Filter: filter})
+		if expected := []string{page.Domains[1].Name}; !slices.Equal(names, expected) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []models.DomainFilter{
+			{Status: "degraded"},
+			{MaxCheckAge: -time.Minute},
+			{MinCheckAge: time.Hour, MaxCheckAge: time.Minute},
+		}
+		for _, filter := range invalid {
+			_, err := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10, Filter: filter})
+			if !errors.Is(err, models.ErrInvalidFilter) {
+				t.Errorf("Expected ErrInvalidFilter for %+v, got %v", filter, err)
+			}
+		}
+	})
+}
+
+func TestListDomainsSortByCheck(t *testing.T) {
+	storage := newFilterStorage(t)
+
+	tests := []struct {
+		sortBy   models.SortField
+		order    models.SortOrder
+		expected []string
+	}{
+		// Domínios nunca verificados vêm antes na ordem ascendente
+		{models.SortByLastCheckedAt, models.SortAsc, []string{"shop-staging", "blog", "shop", "api"}},
+		{models.SortByLastCheckedAt, models.SortDesc, []string{"api", "shop", "blog", "shop-staging"}},
+	}
+
+	for _, tt := range tests {
+		names := listNames(t, storage, models.ListOptions{Page: 1, PageSize: 10, SortBy: tt.sortBy, Order: tt.order})
+		if !slices.Equal(names, tt.expected) {
+			t.Errorf("%s %s: expected %v, got %v", tt.sortBy, tt.order, tt.expected, names)
+		}
+	}
+
+	// Status são ordenados como texto: down, unknown, up
+	page, err := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []models.DomainStatus
+	for _, d := range page.Domains {
+		statuses = append(statuses, d.Status)
+	}
+	expected := []models.DomainStatus{models.StatusDown, models.StatusUnknown, models.StatusUp, models.StatusUp}
+	if !slices.Equal(statuses, expected) {
+		t.Errorf("Expected %v, got %v", expected, statuses)
+	}
+}
+
+// TestDomainStatusFollowsLatestResult testa que o status acompanha o
+// resultado mais recente, mesmo quando os resultados chegam fora de ordem
+func TestDomainStatusFollowsLatestResult(t *testing.T) {
+	storage, domainID := newStorageWithDomain(t)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(statusCode int, checkedAt time.Time) {
+		t.Helper()
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(domainID).WithStatusCode(statusCode).WithCheckedAt(checkedAt).Build()
+		if err := storage.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if domain, _ := storage.GetDomain(domainID); domain.Status != models.StatusUnknown || !domain.LastCheckedAt.IsZero() {
+		t.Fatalf("Expected unknown status before any check, got %q at %v", domain.Status, domain.LastCheckedAt)
+	}
+
+	save(500, base.Add(time.Minute))
+	save(200, base)
+
+	domain, err := storage.GetDomain(domainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain.Status != models.StatusDown || !domain.LastCheckedAt.Equal(base.Add(time.Minute)) {
+		t.Errorf("Expected down at %v, got %q at %v", base.Add(time.Minute), domain.Status, domain.LastCheckedAt)
+	}
+
+	// UpdateDomain não altera os campos mantidos pelo storage
+	domain.Status = models.StatusUp
+	domain.LastCheckedAt = time.Time{}
+	if err := storage.UpdateDomain(domain); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := storage.GetDomain(domainID); stored.Status != models.StatusDown || stored.LastCheckedAt.IsZero() {
+		t.Errorf("Expected UpdateDomain to keep the check status, got %q at %v", stored.Status, stored.LastCheckedAt)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/memory"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+// TestListDomainsPagination testa que as páginas seguem a ordem de criação,
+// sem repetir nem omitir domínios
+func TestListDomainsPagination(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+
+	var ids []uuid.UUID
+	for range 7 {
+		id, err := storage.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Repete a listagem para pegar variações da iteração do map
+	for range 10 {
+		var listed []uuid.UUID
+		for page := 1; page <= 3; page++ {
+			result, err := storage.ListDomains(models.ListOptions{Page: page, PageSize: 3})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Total != len(ids) || result.TotalPages() != 3 {
+				t.Fatalf("Expected total %d in 3 pages, got %d in %d", len(ids), result.Total, result.TotalPages())
+			}
+			for _, d := range result.Domains {
+				listed = append(listed, d.ID)
+			}
+		}
+
+		if !slices.Equal(listed, ids) {
+			t.Fatalf("Expected domains in creation order %v, got %v", ids, listed)
+		}
+	}
+
+	t.Run("Past Last Page", func(t *testing.T) {
+		result, err := storage.ListDomains(models.ListOptions{Page: 5, PageSize: 3})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Domains) != 0 || result.Total != len(ids) {
+			t.Errorf("Expected empty page with total %d, got %d domains and total %d", len(ids), len(result.Domains), result.Total)
+		}
+	})
+}
+
+func TestListDomainsSorted(t *testing.T) {
+	storage := memory.NewMemoryStorage()
+	for _, name := range []string{"bravo", "alpha", "charlie", "alpha"} {
+		if _, err := storage.CreateDomain(helpers.NewTestDomainBuilder().WithName(name).Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := storage.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var names []string
+	for _, d := range result.Domains {
+		names = append(names, d.Name)
+	}
+	if expected := []string{"alpha", "alpha", "bravo", "charlie"}; !slices.Equal(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	// Nomes iguais são desempatados pelo ID
+	first, second := result.Domains[0].ID, result.Domains[1].ID
+	if first.String() > second.String() {
+		t.Errorf("Expected ties ordered by ID, got %v before %v", first, second)
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []models.ListOptions{
+			{Page: 1, PageSize: 10, SortBy: "ip"},
+			{Page: 1, PageSize: 10, Order: "up"},
+		}
+		for _, opts := range invalid {
+			if _, err := storage.ListDomains(opts); !errors.Is(err, models.ErrInvalidSort) {
+				t.Errorf("Expected ErrInvalidSort for %+v, got %v", opts, err)
+			}
+		}
+		if _, err := storage.ListDomains(models.ListOptions{}); !errors.Is(err, models.ErrInvalidPage) {
+			t.Errorf("Expected ErrInvalidPage, got %v", err)
+		}
+	})
+}
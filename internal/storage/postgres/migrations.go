@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID identifica o advisory lock que serializa as migrações
+// quando várias instâncias iniciam ao mesmo tempo
+const migrationLockID = 7301542019
+
+// migration é uma versão do schema. As versões são aplicadas em ordem, cada
+// uma em sua transação, e registradas em schema_migrations.
+type migration struct {
+	version    int
+	statements []string
+}
+
+// migrations lista as versões do schema. Nunca altere uma versão já publicada;
+// acrescente uma nova.
+var migrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE domains (
+				id         UUID PRIMARY KEY,
+				name       TEXT NOT NULL,
+				url        TEXT NOT NULL,
+				timeout    INTEGER NOT NULL DEFAULT 0,
+				"interval" INTEGER NOT NULL DEFAULT 0,
+				ip         TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL
+			)`,
+			`CREATE INDEX idx_domains_created_at ON domains (created_at, id)`,
+			`CREATE TABLE check_results (
+				seq              BIGINT GENERATED ALWAYS AS IDENTITY,
+				id               UUID PRIMARY KEY,
+				domain_id        UUID NOT NULL REFERENCES domains (id) ON DELETE CASCADE,
+				status_code      INTEGER NOT NULL DEFAULT 0,
+				response_time_ms BIGINT NOT NULL DEFAULT 0,
+				error            TEXT NOT NULL DEFAULT '',
+				error_kind       TEXT NOT NULL DEFAULT '',
+				redirect_url     TEXT NOT NULL DEFAULT '',
+				redirect_count   INTEGER NOT NULL DEFAULT 0,
+				checked_at       TIMESTAMPTZ NOT NULL,
+				content_length   BIGINT NOT NULL DEFAULT 0,
+				server           TEXT NOT NULL DEFAULT '',
+				resolved_ip      TEXT NOT NULL DEFAULT '',
+				resolved_ips     TEXT[],
+				unexpected_ip    BOOLEAN NOT NULL DEFAULT FALSE,
+				redirect_chain   JSONB,
+				tls              JSONB
+			)`,
+			`CREATE INDEX idx_check_results_domain_checked_at ON check_results (domain_id, checked_at DESC)`,
+			`CREATE TABLE dns_changes (
+				seq              BIGINT GENERATED ALWAYS AS IDENTITY,
+				id               UUID PRIMARY KEY,
+				domain_id        UUID NOT NULL REFERENCES domains (id) ON DELETE CASCADE,
+				previous_ips     TEXT[],
+				current_ips      TEXT[],
+				expected_ips     TEXT[],
+				matches_expected BOOLEAN NOT NULL DEFAULT FALSE,
+				detected_at      TIMESTAMPTZ NOT NULL
+			)`,
+			`CREATE INDEX idx_dns_changes_domain_detected_at ON dns_changes (domain_id, detected_at)`,
+		},
+	},
+	{
+		version: 2,
+		statements: []string{
+			`ALTER TABLE domains
+				ADD COLUMN tags            TEXT[],
+				ADD COLUMN status          TEXT NOT NULL DEFAULT 'unknown',
+				ADD COLUMN last_checked_at TIMESTAMPTZ`,
+			// Preenche a partir dos resultados já gravados, com a regra de
+			// models.CheckResult.Status
+			`UPDATE domains SET status = latest.status, last_checked_at = latest.checked_at
+			FROM (
+				SELECT DISTINCT ON (domain_id) domain_id, checked_at,
+					CASE WHEN error = '' AND status_code BETWEEN 200 AND 399 THEN 'up' ELSE 'down' END AS status
+				FROM check_results
+				ORDER BY domain_id, checked_at DESC, seq DESC
+			) AS latest
+			WHERE domains.id = latest.domain_id`,
+			`CREATE INDEX idx_domains_status ON domains (status)`,
+			`CREATE INDEX idx_domains_last_checked_at ON domains (last_checked_at)`,
+			`CREATE INDEX idx_domains_tags ON domains USING GIN (tags)`,
+		},
+	},
+	{
+		// Falha se o banco já tiver URLs repetidas, que devem ser removidas
+		// antes da atualização
+		version: 3,
+		statements: []string{
+			`CREATE UNIQUE INDEX idx_domains_url ON domains (url)`,
+		},
+	},
+	{
+		// Duração de cada etapa da verificação; nula nos resultados anteriores
+		version: 4,
+		statements: []string{
+			`ALTER TABLE check_results ADD COLUMN timings JSONB`,
+		},
+	},
+	{
+		// Asserções de conteúdo por domínio e as falhas de cada verificação
+		version: 5,
+		statements: []string{
+			`ALTER TABLE domains ADD COLUMN assertions JSONB`,
+			`ALTER TABLE check_results ADD COLUMN assertion_failures JSONB`,
+		},
+	},
+	{
+		// Índice na ordem das listagens de resultados, com o ID como desempate;
+		// substitui o índice por (domain_id, checked_at), que fica redundante
+		version: 6,
+		statements: []string{
+			`CREATE INDEX idx_check_results_domain_checked_at_id ON check_results (domain_id, checked_at DESC, id DESC)`,
+			`DROP INDEX IF EXISTS idx_check_results_domain_checked_at`,
+		},
+	},
+	{
+		// Status aceito por Assertions.StatusCodes; falso nos resultados anteriores
+		version: 7,
+		statements: []string{
+			`ALTER TABLE check_results ADD COLUMN expected_status BOOLEAN NOT NULL DEFAULT FALSE`,
+		},
+	},
+}
+
+// migrate aplica as versões do schema ainda não registradas no banco
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	for _, m := range migrations {
+		if err := applyMigration(ctx, pool, m); err != nil {
+			return fmt.Errorf("postgres: migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration aplica m se ainda não foi aplicada. O advisory lock da
+// transação garante que só uma instância migra por vez.
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, m migration) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+			return err
+		}
+
+		var applied bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`,
+			m.version).Scan(&applied); err != nil {
+			return err
+		}
+		if applied {
+			return nil
+		}
+
+		for _, statement := range m.statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.version)
+		return err
+	})
+}
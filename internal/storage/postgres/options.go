@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Opções do pool aceitas por NewPostgresStorageWithOptions. Sobrescrevem os
+// parâmetros pool_* equivalentes do DSN.
+const (
+	// OptionMaxConns é o número máximo de conexões do pool
+	OptionMaxConns = "max_conns"
+	// OptionMinConns é o número mínimo de conexões mantidas abertas
+	OptionMinConns = "min_conns"
+	// OptionMaxConnLifetime é o tempo máximo de vida de uma conexão
+	OptionMaxConnLifetime = "max_conn_lifetime"
+	// OptionMaxConnIdleTime é o tempo máximo que uma conexão fica ociosa
+	OptionMaxConnIdleTime = "max_conn_idle_time"
+	// OptionHealthCheckPeriod é o intervalo de verificação das conexões ociosas
+	OptionHealthCheckPeriod = "health_check_period"
+)
+
+// applyOptions aplica as opções ao config do pool. Inteiros aceitam int ou
+// float64 (como vêm do JSON) e durações aceitam time.Duration ou uma string
+// no formato de time.ParseDuration.
+func applyOptions(config *pgxpool.Config, options map[string]any) error {
+	for key, value := range options {
+		var err error
+		switch key {
+		case OptionMaxConns:
+			config.MaxConns, err = int32Option(value)
+		case OptionMinConns:
+			config.MinConns, err = int32Option(value)
+		case OptionMaxConnLifetime:
+			config.MaxConnLifetime, err = durationOption(value)
+		case OptionMaxConnIdleTime:
+			config.MaxConnIdleTime, err = durationOption(value)
+		case OptionHealthCheckPeriod:
+			config.HealthCheckPeriod, err = durationOption(value)
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+		if err != nil {
+			return fmt.Errorf("%w %q: %v", ErrInvalidOption, key, err)
+		}
+	}
+	return nil
+}
+
+func int32Option(value any) (int32, error) {
+	var n float64
+	switch v := value.(type) {
+	case int:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", value)
+	}
+	if n != math.Trunc(n) || n < 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("expected a non-negative integer, got %v", value)
+	}
+	return int32(n), nil
+}
+
+func durationOption(value any) (time.Duration, error) {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, err
+		}
+		d = parsed
+	default:
+		return 0, fmt.Errorf("expected a duration, got %T", value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("expected a non-negative duration, got %v", d)
+	}
+	return d, nil
+}
@@ -0,0 +1,648 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/errs"
+)
+
+const (
+	// foreignKeyViolation é o SQLSTATE de violação de chave estrangeira
+	foreignKeyViolation = "23503"
+	// uniqueViolation é o SQLSTATE de violação de índice único
+	uniqueViolation = "23505"
+	// timestampPrecision é a precisão dos tipos TIMESTAMPTZ do PostgreSQL
+	timestampPrecision = time.Microsecond
+)
+
+var (
+	// ErrDomainNotFound é retornado quando um domínio não é encontrado
+	ErrDomainNotFound = errs.ErrDomainNotFound
+	// ErrCheckResultNotFound é retornado quando o domínio ainda não tem resultados
+	ErrCheckResultNotFound = errs.ErrCheckResultNotFound
+	// ErrDNSChangeNotFound é retornado quando o domínio ainda não tem mudanças de IP
+	ErrDNSChangeNotFound = errs.ErrDNSChangeNotFound
+	// ErrDomainAlreadyExists é retornado quando outro domínio já usa a mesma URL
+	ErrDomainAlreadyExists = errs.ErrDomainAlreadyExists
+	// ErrEmptyDSN é retornado quando a string de conexão não é informada
+	ErrEmptyDSN = errors.New("postgres: empty DSN")
+	// ErrInvalidOption é retornado quando uma opção do pool é desconhecida ou
+	// tem um valor inválido
+	ErrInvalidOption = errors.New("postgres: invalid option")
+)
+
+// PostgresStorage é uma implementação do Storage no PostgreSQL, com um pool
+// de conexões. O pool é configurado pelos parâmetros pool_* do DSN, como
+// pool_max_conns e pool_max_conn_idle_time, ou pelas opções Option*.
+type PostgresStorage struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStorage conecta ao banco de dsn e aplica as migrações pendentes
+func NewPostgresStorage(dsn string) (*PostgresStorage, error) {
+	return NewPostgresStorageWithOptions(dsn, nil)
+}
+
+// NewPostgresStorageWithOptions é como NewPostgresStorage, mas aplica as
+// opções do pool (chaves Option*) sobre a configuração lida do DSN
+func NewPostgresStorageWithOptions(dsn string, options map[string]any) (*PostgresStorage, error) {
+	if dsn == "" {
+		return nil, ErrEmptyDSN
+	}
+
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyOptions(config, options); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	if err := migrate(ctx, pool); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &PostgresStorage{pool: pool}, nil
+}
+
+// Close fecha todas as conexões do pool
+func (p *PostgresStorage) Close() error {
+	p.pool.Close()
+	return nil
+}
+
+// CreateDomain cria um novo domínio no storage
+func (p *PostgresStorage) CreateDomain(domain *models.Domain) (uuid.UUID, error) {
+	return p.CreateDomainContext(context.Background(), domain)
+}
+
+func (p *PostgresStorage) CreateDomainContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error) {
+	domain.ID = uuid.New()
+
+	// Define timestamps na precisão do banco
+	now := time.Now().Truncate(timestampPrecision)
+	domain.CreatedAt = now
+	domain.UpdatedAt = now
+
+	// O status vem das verificações gravadas depois
+	domain.Status = models.StatusUnknown
+	domain.LastCheckedAt = time.Time{}
+
+	_, err := p.pool.Exec(ctx,
+		`INSERT INTO domains (id, name, url, timeout, "interval", ip, tags, assertions, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		domain.ID, domain.Name, domain.URL, domain.Timeout, domain.Interval, domain.IP,
+		domain.Tags, domain.Assertions, string(domain.Status), domain.CreatedAt, domain.UpdatedAt)
+	if err != nil {
+		return uuid.Nil, uniqueError(err)
+	}
+
+	return domain.ID, nil
+}
+
+// GetDomain busca um domínio pelo ID
+func (p *PostgresStorage) GetDomain(id uuid.UUID) (*models.Domain, error) {
+	return p.GetDomainContext(context.Background(), id)
+}
+
+func (p *PostgresStorage) GetDomainContext(ctx context.Context, id uuid.UUID) (*models.Domain, error) {
+	row := p.pool.QueryRow(ctx, `SELECT `+domainColumns+` FROM domains WHERE id = $1`, id)
+
+	domain, err := scanDomain(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDomainNotFound
+	}
+	return domain, err
+}
+
+// ListDomains retorna uma página de domínios na ordem definida por opts
+func (p *PostgresStorage) ListDomains(opts models.ListOptions) (*models.DomainPage, error) {
+	return p.ListDomainsContext(context.Background(), opts)
+}
+
+func (p *PostgresStorage) ListDomainsContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error) {
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	where, args := filterDomains(opts.Filter, time.Now())
+
+	var total int
+	if err := p.pool.QueryRow(ctx, `SELECT count(*) FROM domains`+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM domains%s ORDER BY %s LIMIT $%d OFFSET $%d`,
+		domainColumns, where, orderBy(opts), len(args)+1, len(args)+2)
+	rows, err := p.pool.Query(ctx, query, append(args, opts.PageSize, opts.Offset())...)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Domain, error) {
+		return scanDomain(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DomainPage{
+		Domains:  domains,
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// ListDomainsCursor retorna os domínios após opts.Cursor na ordem definida por opts
+func (p *PostgresStorage) ListDomainsCursor(opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	return p.ListDomainsCursorContext(context.Background(), opts)
+}
+
+func (p *PostgresStorage) ListDomainsCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	listOpts, cursor, err := opts.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := filterDomains(listOpts.Filter, time.Now())
+	if cursor != nil {
+		var condition string
+		condition, args = afterDomain(listOpts, cursor, args)
+		if where == "" {
+			where = ` WHERE ` + condition
+		} else {
+			where += ` AND ` + condition
+		}
+	}
+
+	query := `SELECT ` + domainColumns + ` FROM domains` + where
+
+	// Um domínio além do limite indica que há próxima página
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy(listOpts), len(args)+1)
+	args = append(args, opts.Limit+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Domain, error) {
+		return scanDomain(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.NewDomainCursorPage(domains, listOpts, opts.Limit), nil
+}
+
+// UpdateDomain atualiza um domínio existente
+func (p *PostgresStorage) UpdateDomain(domain *models.Domain) error {
+	return p.UpdateDomainContext(context.Background(), domain)
+}
+
+func (p *PostgresStorage) UpdateDomainContext(ctx context.Context, domain *models.Domain) error {
+	domain.UpdatedAt = time.Now().Truncate(timestampPrecision)
+
+	// status e last_checked_at são mantidos por SaveCheckResult
+	tag, err := p.pool.Exec(ctx,
+		`UPDATE domains SET name = $1, url = $2, timeout = $3, "interval" = $4, ip = $5, tags = $6, assertions = $7,
+		updated_at = $8 WHERE id = $9`,
+		domain.Name, domain.URL, domain.Timeout, domain.Interval, domain.IP, domain.Tags, domain.Assertions,
+		domain.UpdatedAt, domain.ID)
+	if err != nil {
+		return uniqueError(err)
+	}
+
+	return requireRow(tag)
+}
+
+// DeleteDomain remove um domínio junto com seus resultados e mudanças de IP
+func (p *PostgresStorage) DeleteDomain(id uuid.UUID) error {
+	return p.DeleteDomainContext(context.Background(), id)
+}
+
+func (p *PostgresStorage) DeleteDomainContext(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM domains WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(tag)
+}
+
+// SaveDNSChange registra uma mudança de IP no histórico do domínio
+func (p *PostgresStorage) SaveDNSChange(event *models.DNSChangeEvent) error {
+	return p.SaveDNSChangeContext(context.Background(), event)
+}
+
+func (p *PostgresStorage) SaveDNSChangeContext(ctx context.Context, event *models.DNSChangeEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	event.DetectedAt = event.DetectedAt.Truncate(timestampPrecision)
+
+	_, err := p.pool.Exec(ctx, `INSERT INTO dns_changes
+		(id, domain_id, previous_ips, current_ips, expected_ips, matches_expected, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.ID, event.DomainID, event.PreviousIPs, event.CurrentIPs, event.ExpectedIPs,
+		event.MatchesExpected, event.DetectedAt)
+	return foreignKeyError(err)
+}
+
+// ListDNSChanges retorna o histórico de mudanças de IP do domínio, do mais antigo ao mais recente
+func (p *PostgresStorage) ListDNSChanges(domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	return p.ListDNSChangesContext(context.Background(), domainID)
+}
+
+func (p *PostgresStorage) ListDNSChangesContext(ctx context.Context, domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	if err := p.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.pool.Query(ctx, `SELECT `+dnsChangeColumns+`
+		FROM dns_changes WHERE domain_id = $1 ORDER BY detected_at, seq`, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.DNSChangeEvent, error) {
+		return scanDNSChange(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatestDNSChange retorna a mudança de IP mais recente do domínio
+func (p *PostgresStorage) LatestDNSChange(domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	return p.LatestDNSChangeContext(context.Background(), domainID)
+}
+
+func (p *PostgresStorage) LatestDNSChangeContext(ctx context.Context, domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	if err := p.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	row := p.pool.QueryRow(ctx, `SELECT `+dnsChangeColumns+` FROM dns_changes
+		WHERE domain_id = $1 ORDER BY detected_at DESC, seq DESC LIMIT 1`, domainID)
+
+	event, err := scanDNSChange(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDNSChangeNotFound
+	}
+	return event, err
+}
+
+// SaveCheckResult registra o resultado de uma verificação do domínio
+func (p *PostgresStorage) SaveCheckResult(result *models.CheckResult) error {
+	return p.SaveCheckResultContext(context.Background(), result)
+}
+
+func (p *PostgresStorage) SaveCheckResultContext(ctx context.Context, result *models.CheckResult) error {
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+	}
+	if result.CheckedAt.IsZero() {
+		result.CheckedAt = time.Now()
+	}
+	// Trunca como o banco armazena, para o resultado salvo ser igual ao lido
+	result.CheckedAt = result.CheckedAt.Truncate(timestampPrecision)
+
+	err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO check_results (`+checkResultColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`,
+			result.ID, result.DomainID, result.StatusCode, result.ResponseTime,
+			result.Error, string(result.ErrorKind), result.RedirectURL, result.RedirectCount,
+			result.CheckedAt, result.ContentLength, result.Server, result.ResolvedIP,
+			result.ResolvedIPs, result.UnexpectedIP, result.RedirectChain, result.TLS, result.Timings,
+			result.AssertionFailures, result.ExpectedStatus)
+		if err != nil {
+			return err
+		}
+
+		// O status do domínio acompanha o resultado mais recente
+		_, err = tx.Exec(ctx, `UPDATE domains SET status = $1, last_checked_at = $2
+			WHERE id = $3 AND (last_checked_at IS NULL OR last_checked_at <= $2)`,
+			string(result.Status()), result.CheckedAt, result.DomainID)
+		return err
+	})
+	return foreignKeyError(err)
+}
+
+// ListCheckResults retorna os resultados do domínio no intervalo [from, to),
+// do mais recente ao mais antigo, com paginação
+func (p *PostgresStorage) ListCheckResults(domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	return p.ListCheckResultsContext(context.Background(), domainID, from, to, page, pageSize)
+}
+
+func (p *PostgresStorage) ListCheckResultsContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("page and pageSize must be greater than 0")
+	}
+
+	if err := p.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	// Limites zerados viram NULL e não restringem o intervalo
+	rows, err := p.pool.Query(ctx, `SELECT `+checkResultColumns+` FROM check_results
+		WHERE domain_id = $1
+			AND ($2::timestamptz IS NULL OR checked_at >= $2)
+			AND ($3::timestamptz IS NULL OR checked_at < $3)
+		ORDER BY checked_at DESC, id DESC LIMIT $4 OFFSET $5`,
+		domainID, nullTime(from), nullTime(to), pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.CheckResult, error) {
+		return scanCheckResult(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListCheckResultsCursor retorna os resultados do domínio no intervalo
+// [from, to) após cursor, do mais recente ao mais antigo
+func (p *PostgresStorage) ListCheckResultsCursor(domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	return p.ListCheckResultsCursorContext(context.Background(), domainID, from, to, cursor, limit)
+}
+
+func (p *PostgresStorage) ListCheckResultsCursorContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	if limit < 1 {
+		return nil, models.ErrInvalidLimit
+	}
+
+	// Sem cursor, os parâmetros do cursor ficam NULL e não restringem a busca
+	var afterTime, afterID any
+	if cursor != "" {
+		after, err := models.DecodeCheckResultCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		afterTime, afterID = after.CheckedAt, after.ID
+	}
+
+	if err := p.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	// Um resultado além do limite indica que há próxima página
+	rows, err := p.pool.Query(ctx, `SELECT `+checkResultColumns+` FROM check_results
+		WHERE domain_id = $1
+			AND ($2::timestamptz IS NULL OR checked_at >= $2)
+			AND ($3::timestamptz IS NULL OR checked_at < $3)
+			AND ($4::timestamptz IS NULL OR (checked_at, id) < ($4, $5::uuid))
+		ORDER BY checked_at DESC, id DESC LIMIT $6`,
+		domainID, nullTime(from), nullTime(to), afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.CheckResult, error) {
+		return scanCheckResult(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCheckResultCursorPage(results, limit), nil
+}
+
+// LatestCheckResult retorna o resultado mais recente do domínio
+func (p *PostgresStorage) LatestCheckResult(domainID uuid.UUID) (*models.CheckResult, error) {
+	return p.LatestCheckResultContext(context.Background(), domainID)
+}
+
+func (p *PostgresStorage) LatestCheckResultContext(ctx context.Context, domainID uuid.UUID) (*models.CheckResult, error) {
+	if err := p.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	row := p.pool.QueryRow(ctx, `SELECT `+checkResultColumns+` FROM check_results
+		WHERE domain_id = $1 ORDER BY checked_at DESC, id DESC LIMIT 1`, domainID)
+
+	result, err := scanCheckResult(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCheckResultNotFound
+	}
+	return result, err
+}
+
+const domainColumns = `id, name, url, timeout, "interval", ip, tags, assertions, status, created_at, updated_at,
+	last_checked_at`
+
+const checkResultColumns = `id, domain_id, status_code, response_time_ms, error, error_kind,
+	redirect_url, redirect_count, checked_at, content_length, server, resolved_ip,
+	resolved_ips, unexpected_ip, redirect_chain, tls, timings, assertion_failures, expected_status`
+
+// sortColumns mapeia os campos de ordenação para as colunas da tabela
+// domains. Os textos usam a collation "C", que compara bytes como os demais
+// backends, em vez da collation do banco.
+var sortColumns = map[models.SortField]string{
+	models.SortByCreatedAt: "created_at",
+	models.SortByUpdatedAt: "updated_at",
+	models.SortByName:      `name COLLATE "C"`,
+	models.SortByURL:       `url COLLATE "C"`,
+	models.SortByStatus:    `status COLLATE "C"`,
+	// Domínios nunca verificados ficam antes de todos, como o time.Time zero
+	models.SortByLastCheckedAt: `COALESCE(last_checked_at, '0001-01-01 00:00:00+00')`,
+}
+
+// orderBy monta a cláusula ORDER BY de opts, já validado, com o ID como desempate
+func orderBy(opts models.ListOptions) string {
+	direction := " ASC"
+	if opts.Order == models.SortDesc {
+		direction = " DESC"
+	}
+	return sortColumns[opts.SortBy] + direction + ", id" + direction
+}
+
+// afterDomain monta a condição que seleciona os domínios após cursor na
+// ordem de opts, comparando o campo de ordenação e o ID juntos. Os valores
+// são acrescentados a args, que numera os parâmetros.
+func afterDomain(opts models.ListOptions, cursor *models.DomainCursor, args []any) (string, []any) {
+	op := " > "
+	if opts.Order == models.SortDesc {
+		op = " < "
+	}
+
+	var value any = cursor.Value
+	switch opts.SortBy {
+	case models.SortByCreatedAt, models.SortByUpdatedAt, models.SortByLastCheckedAt:
+		value = cursor.Time
+	}
+
+	condition := fmt.Sprintf("(%s, id)%s($%d, $%d)", sortColumns[opts.SortBy], op, len(args)+1, len(args)+2)
+	return condition, append(args, value, cursor.ID)
+}
+
+// filterDomains monta a cláusula WHERE do filtro, vazia se não há restrições
+func filterDomains(filter models.DomainFilter, now time.Time) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	// add acrescenta um valor e retorna o seu parâmetro
+	add := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.Search != "" {
+		search := add(filter.Search)
+		conditions = append(conditions,
+			"(strpos(lower(name), lower("+search+")) > 0 OR strpos(lower(url), lower("+search+")) > 0)")
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "tags @> ARRAY["+add(filter.Tag)+"::text]")
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+add(string(filter.Status)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= "+add(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < "+add(filter.CreatedBefore))
+	}
+
+	// last_checked_at nulo nunca satisfaz as comparações
+	from, to := filter.LastCheckRange(now)
+	if !from.IsZero() {
+		conditions = append(conditions, "last_checked_at >= "+add(from))
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, "last_checked_at <= "+add(to))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanDomain(row pgx.Row) (*models.Domain, error) {
+	var (
+		domain        models.Domain
+		status        string
+		lastCheckedAt *time.Time
+	)
+	if err := row.Scan(&domain.ID, &domain.Name, &domain.URL, &domain.Timeout, &domain.Interval,
+		&domain.IP, &domain.Tags, &domain.Assertions, &status, &domain.CreatedAt, &domain.UpdatedAt,
+		&lastCheckedAt); err != nil {
+		return nil, err
+	}
+
+	// O pgx devolve TIMESTAMPTZ no fuso local; o storage trabalha em UTC
+	domain.Status = models.DomainStatus(status)
+	domain.CreatedAt = domain.CreatedAt.UTC()
+	domain.UpdatedAt = domain.UpdatedAt.UTC()
+	if lastCheckedAt != nil {
+		domain.LastCheckedAt = lastCheckedAt.UTC()
+	}
+	return &domain, nil
+}
+
+const dnsChangeColumns = `id, domain_id, previous_ips, current_ips, expected_ips, matches_expected, detected_at`
+
+func scanDNSChange(row pgx.Row) (*models.DNSChangeEvent, error) {
+	var event models.DNSChangeEvent
+	if err := row.Scan(&event.ID, &event.DomainID, &event.PreviousIPs, &event.CurrentIPs,
+		&event.ExpectedIPs, &event.MatchesExpected, &event.DetectedAt); err != nil {
+		return nil, err
+	}
+	event.DetectedAt = event.DetectedAt.UTC()
+	return &event, nil
+}
+
+func scanCheckResult(row pgx.Row) (*models.CheckResult, error) {
+	var (
+		result    models.CheckResult
+		errorKind string
+	)
+	if err := row.Scan(&result.ID, &result.DomainID, &result.StatusCode, &result.ResponseTime,
+		&result.Error, &errorKind, &result.RedirectURL, &result.RedirectCount, &result.CheckedAt,
+		&result.ContentLength, &result.Server, &result.ResolvedIP, &result.ResolvedIPs,
+		&result.UnexpectedIP, &result.RedirectChain, &result.TLS, &result.Timings,
+		&result.AssertionFailures, &result.ExpectedStatus); err != nil {
+		return nil, err
+	}
+
+	result.ErrorKind = models.ErrorKind(errorKind)
+	result.CheckedAt = result.CheckedAt.UTC()
+	return &result, nil
+}
+
+// requireDomain retorna ErrDomainNotFound quando o domínio não existe
+func (p *PostgresStorage) requireDomain(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := p.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM domains WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDomainNotFound
+	}
+	return nil
+}
+
+// requireRow retorna ErrDomainNotFound quando a operação não afetou nenhuma linha
+func requireRow(tag pgconn.CommandTag) error {
+	if tag.RowsAffected() == 0 {
+		return ErrDomainNotFound
+	}
+	return nil
+}
+
+// uniqueError converte a violação do índice único de URL em ErrDomainAlreadyExists
+func uniqueError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrDomainAlreadyExists
+	}
+	return err
+}
+
+// foreignKeyError converte a violação da chave estrangeira domain_id em ErrDomainNotFound
+func foreignKeyError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return ErrDomainNotFound
+	}
+	return err
+}
+
+// nullTime converte o horário zero em NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/luizhreis/domain-watcher/internal/storage/postgres"
+)
+
+// dsnEnv é a variável com a string de conexão de um PostgreSQL de testes.
+// Sem ela, os testes iniciam um cluster local com initdb e pg_ctl.
+const dsnEnv = "DOMAIN_WATCHER_POSTGRES_DSN"
+
+var (
+	// baseDSN é a conexão com o banco de testes; vazia se não há PostgreSQL
+	baseDSN string
+	// skipReason explica por que os testes são ignorados
+	skipReason string
+)
+
+func TestMain(m *testing.M) {
+	stop := setup()
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
+
+// setup define baseDSN a partir do ambiente ou de um cluster local e retorna
+// a função que encerra o cluster
+func setup() func() {
+	if dsn := os.Getenv(dsnEnv); dsn != "" {
+		baseDSN = dsn
+		return func() {}
+	}
+
+	dsn, stop, err := startLocalCluster()
+	if err != nil {
+		skipReason = fmt.Sprintf("PostgreSQL unavailable (set %s): %v", dsnEnv, err)
+		return func() {}
+	}
+
+	baseDSN = dsn
+	return stop
+}
+
+// startLocalCluster cria e inicia um cluster temporário em uma porta livre
+func startLocalCluster() (string, func(), error) {
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		return "", nil, err
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "domain-watcher-postgres-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "--auth=trust").CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("initdb: %w: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1", port, dir)
+	start := exec.Command(pgCtl, "-D", data, "-o", options, "-l", filepath.Join(dir, "postgres.log"), "-w", "start")
+	if out, err := start.CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("pg_ctl start: %w: %s", err, out)
+	}
+
+	stop := func() {
+		_ = exec.Command(pgCtl, "-D", data, "-m", "immediate", "stop").Run()
+		cleanup()
+	}
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port), stop, nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// newTestDSN cria um schema exclusivo para o teste e retorna um DSN que o usa,
+// de modo que cada teste parte de um banco sem migrações
+func newTestDSN(t *testing.T) string {
+	t.Helper()
+
+	if baseDSN == "" {
+		t.Skip(skipReason)
+	}
+
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, baseDSN)
+	if err != nil {
+		t.Fatalf("Expected no error connecting to PostgreSQL, got %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("Expected no error creating schema, got %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, _ = admin.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`)
+	})
+
+	return withParam(t, baseDSN, "search_path", schema)
+}
+
+// withParam acrescenta um parâmetro ao DSN, em formato URL ou chave=valor
+func withParam(t *testing.T, dsn, key, value string) string {
+	t.Helper()
+
+	if !strings.Contains(dsn, "://") {
+		return dsn + " " + key + "=" + value
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("Invalid DSN: %v", err)
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// newTestStorage abre o storage em um schema exclusivo do teste
+func newTestStorage(t *testing.T) (*postgres.PostgresStorage, string) {
+	t.Helper()
+
+	dsn := newTestDSN(t)
+	s, err := postgres.NewPostgresStorage(dsn)
+	if err != nil {
+		t.Fatalf("Expected no error opening storage, got %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, dsn
+}
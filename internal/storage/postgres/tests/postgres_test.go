@@ -0,0 +1,775 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage"
+	"github.com/luizhreis/domain-watcher/internal/storage/postgres"
+	"github.com/luizhreis/domain-watcher/tests/helpers"
+)
+
+func TestNewPostgresStorage(t *testing.T) {
+	t.Run("Empty DSN", func(t *testing.T) {
+		if _, err := postgres.NewPostgresStorage(""); !errors.Is(err, postgres.ErrEmptyDSN) {
+			t.Errorf("Expected ErrEmptyDSN, got %v", err)
+		}
+	})
+
+	t.Run("Factory Empty DSN", func(t *testing.T) {
+		s, err := storage.NewStorage(&storage.StorageConfig{Type: storage.StorageTypePostgreSQL})
+		if err == nil || s != nil {
+			t.Errorf("Expected error and nil storage, got %v and %v", err, s)
+		}
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		for _, options := range []map[string]any{
+			{"unknown": 1},
+			{postgres.OptionMaxConns: "ten"},
+			{postgres.OptionMaxConns: 1.5},
+			{postgres.OptionMinConns: -1},
+			{postgres.OptionMaxConnIdleTime: "soon"},
+			{postgres.OptionHealthCheckPeriod: 30},
+		} {
+			// As opções são validadas antes de conectar, então não precisa de banco
+			_, err := postgres.NewPostgresStorageWithOptions("postgres://localhost/domain_watcher", options)
+			if !errors.Is(err, postgres.ErrInvalidOption) {
+				t.Errorf("Expected ErrInvalidOption for %v, got %v", options, err)
+			}
+		}
+	})
+
+	t.Run("Factory With Options", func(t *testing.T) {
+		dsn := newTestDSN(t)
+		s, err := storage.NewStorage(&storage.StorageConfig{
+			Type: storage.StorageTypePostgreSQL,
+			DSN:  dsn,
+			Options: map[string]interface{}{
+				postgres.OptionMaxConns:        float64(3),
+				postgres.OptionMaxConnIdleTime: "1m",
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer s.Close()
+	})
+
+	t.Run("Factory", func(t *testing.T) {
+		dsn := newTestDSN(t)
+		s, err := storage.NewStorage(&storage.StorageConfig{Type: storage.StorageTypePostgreSQL, DSN: dsn})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer s.Close()
+
+		if _, ok := s.(*postgres.PostgresStorage); !ok {
+			t.Errorf("Expected *postgres.PostgresStorage, got %T", s)
+		}
+	})
+}
+
+// TestPostgresMigrations testa que as migrações são aplicadas uma única vez,
+// inclusive com várias instâncias iniciando ao mesmo tempo
+func TestPostgresMigrations(t *testing.T) {
+	dsn := newTestDSN(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := postgres.NewPostgresStorage(dsn)
+			if err == nil {
+				s.Close()
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected concurrent migrations to succeed, got %v", err)
+		}
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	var versions int
+	if err := pool.QueryRow(context.Background(), `SELECT count(*) FROM schema_migrations`).Scan(&versions); err != nil {
+		t.Fatalf("Expected schema_migrations table, got %v", err)
+	}
+	if versions != 7 {
+		t.Errorf("Expected 7 applied migrations, got %d", versions)
+	}
+
+	var indexes []string
+	rows, err := pool.Query(context.Background(), `SELECT indexname FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = 'check_results' ORDER BY indexname`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexes, err = pgx.CollectRows(rows, pgx.RowTo[string]); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(indexes, "idx_check_results_domain_checked_at") {
+		t.Errorf("Expected the redundant v1 index to be dropped, got %v", indexes)
+	}
+
+	var indexdef string
+	err = pool.QueryRow(context.Background(), `SELECT indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = 'check_results'
+			AND indexname = 'idx_check_results_domain_checked_at_id'`).Scan(&indexdef)
+	if err != nil {
+		t.Fatalf("Expected cursor index on check_results, got %v", err)
+	}
+	if !strings.Contains(indexdef, "(domain_id, checked_at DESC, id DESC)") {
+		t.Errorf("Unexpected cursor index definition: %s", indexdef)
+	}
+}
+
+// TestPostgresSurvivesRestart testa que os dados persistem ao reconectar
+func TestPostgresSurvivesRestart(t *testing.T) {
+	s, dsn := newTestStorage(t)
+
+	domain := helpers.NewTestDomainBuilder().WithURL("persist.example.com").WithInterval(120).Build()
+	id, err := s.CreateDomain(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := postgres.NewPostgresStorage(dsn)
+	if err != nil {
+		t.Fatalf("Expected reopen to succeed, got %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.GetDomain(id)
+	if err != nil {
+		t.Fatalf("Expected domain after restart, got %v", err)
+	}
+	if got.URL != "persist.example.com" || got.Interval != 120 {
+		t.Errorf("Unexpected domain after restart: %+v", got)
+	}
+}
+
+func TestPostgresDomainCRUD(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	domain := &models.Domain{Name: "Example", URL: "example.com", Timeout: 10, Interval: 60, IP: "192.0.2.1"}
+	id, err := s.CreateDomain(domain)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id == uuid.Nil || domain.ID != id || domain.CreatedAt.IsZero() {
+		t.Fatalf("Expected ID and timestamps to be assigned, got %+v", domain)
+	}
+
+	got, err := s.GetDomain(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Name != "Example" || got.Timeout != 10 || got.IP != "192.0.2.1" || !got.CreatedAt.Equal(domain.CreatedAt) {
+		t.Errorf("Unexpected domain: %+v", got)
+	}
+
+	// TIMESTAMPTZ tem precisão de microssegundos
+	time.Sleep(time.Millisecond)
+	got.URL = "www.example.com"
+	if err := s.UpdateDomain(got); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated, _ := s.GetDomain(id)
+	if updated.URL != "www.example.com" || !updated.UpdatedAt.After(updated.CreatedAt) {
+		t.Errorf("Expected updated URL and UpdatedAt, got %+v", updated)
+	}
+
+	updated.Assertions = &models.Assertions{
+		StatusCodes:     []int{200},
+		BodyNotContains: []string{"parked"},
+		JSON:            []string{`$.status == "ok"`},
+		Headers:         map[string]string{"X-Service": "api"},
+		MaxLatency:      500,
+	}
+	if err := s.UpdateDomain(updated); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asserted, _ := s.GetDomain(id); !reflect.DeepEqual(asserted.Assertions, updated.Assertions) {
+		t.Errorf("Expected assertions %+v, got %+v", updated.Assertions, asserted.Assertions)
+	}
+
+	if err := s.DeleteDomain(id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := s.GetDomain(id); !errors.Is(err, storage.ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound after delete, got %v", err)
+	}
+
+	t.Run("Not Found", func(t *testing.T) {
+		missing := &models.Domain{ID: uuid.New()}
+		if err := s.UpdateDomain(missing); !errors.Is(err, storage.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound on update, got %v", err)
+		}
+		if err := s.DeleteDomain(missing.ID); !errors.Is(err, storage.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound on delete, got %v", err)
+		}
+	})
+}
+
+func TestPostgresListDomains(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	var ids []uuid.UUID
+	for range 5 {
+		id, err := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	var listed []uuid.UUID
+	for page := 1; page <= 3; page++ {
+		result, err := s.ListDomains(models.ListOptions{Page: page, PageSize: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Total != len(ids) {
+			t.Errorf("Expected total %d, got %d", len(ids), result.Total)
+		}
+		for _, d := range result.Domains {
+			listed = append(listed, d.ID)
+		}
+	}
+
+	if !reflect.DeepEqual(listed, ids) {
+		t.Errorf("Expected domains in creation order %v, got %v", ids, listed)
+	}
+
+	if _, err := s.ListDomains(models.ListOptions{Page: 0, PageSize: 10}); err == nil {
+		t.Error("Expected error for invalid pagination, got nil")
+	}
+	if _, err := s.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: "ip"}); !errors.Is(err, models.ErrInvalidSort) {
+		t.Errorf("Expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestPostgresListDomainsSorted(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	for _, url := range []string{"b.example.com", "C.example.com", "a.example.com"} {
+		if _, err := s.CreateDomain(helpers.NewTestDomainBuilder().WithURL(url).Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		order    models.SortOrder
+		expected []string
+	}{
+		{models.SortAsc, []string{"C.example.com", "a.example.com", "b.example.com"}},
+		{models.SortDesc, []string{"b.example.com", "a.example.com", "C.example.com"}},
+	}
+
+	for _, tt := range tests {
+		result, err := s.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByURL, Order: tt.order})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var urls []string
+		for _, d := range result.Domains {
+			urls = append(urls, d.URL)
+		}
+		if !reflect.DeepEqual(urls, tt.expected) {
+			t.Errorf("Order %s: expected %v, got %v", tt.order, tt.expected, urls)
+		}
+	}
+}
+
+func TestPostgresCheckResults(t *testing.T) {
+	s, _ := newTestStorage(t)
+	domainID, err := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	full := &models.CheckResult{
+		DomainID:      domainID,
+		StatusCode:    301,
+		ResponseTime:  42,
+		ErrorKind:     models.ErrorKindTLSExpired,
+		Error:         "tls certificate expired",
+		RedirectURL:   "https://www.example.com/",
+		RedirectCount: 1,
+		CheckedAt:     base.Add(2 * time.Minute),
+		ResolvedIP:    "192.0.2.1",
+		ResolvedIPs:   []string{"192.0.2.1", "2001:db8::1"},
+		UnexpectedIP:  true,
+		RedirectChain: &models.RedirectChain{
+			Hops:      []models.RedirectHop{{URL: "http://example.com/", StatusCode: 301, Location: "https://www.example.com/"}},
+			Downgrade: false,
+		},
+		TLS:            &models.TLSInfo{Subject: "example.com", SANs: []string{"example.com"}, NotAfter: base, ChainValid: true},
+		Timings:        &models.Timings{DNSLookup: 3, TCPConnect: 5, TLSHandshake: 12, TimeToFirstByte: 20, ContentTransfer: 2},
+		ExpectedStatus: true,
+		AssertionFailures: []models.AssertionFailure{
+			{Assertion: models.AssertionStatusCodes, Expected: "[200]", Actual: "301"},
+		},
+	}
+	if err := s.SaveCheckResult(full); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, offset := range []time.Duration{0, 3 * time.Minute, time.Minute} {
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(domainID).Build()
+		result.CheckedAt = base.Add(offset)
+		if err := s.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		results, err := s.ListCheckResults(domainID, base.Add(2*time.Minute), base.Add(3*time.Minute), 1, 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(results))
+		}
+		if !reflect.DeepEqual(results[0], full) {
+			t.Errorf("Expected %+v, got %+v", full, results[0])
+		}
+	})
+
+	t.Run("Newest First With Pagination", func(t *testing.T) {
+		results, err := s.ListCheckResults(domainID, time.Time{}, time.Time{}, 2, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(results) != 2 || !results[0].CheckedAt.Equal(base.Add(time.Minute)) || !results[1].CheckedAt.Equal(base) {
+			t.Errorf("Unexpected second page: %+v", results)
+		}
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		latest, err := s.LatestCheckResult(domainID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !latest.CheckedAt.Equal(base.Add(3 * time.Minute)) {
+			t.Errorf("Expected latest at %v, got %v", base.Add(3*time.Minute), latest.CheckedAt)
+		}
+	})
+
+	t.Run("Unknown Domain", func(t *testing.T) {
+		result := helpers.NewTestCheckResultBuilder().Build()
+		if err := s.SaveCheckResult(result); !errors.Is(err, storage.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound on save, got %v", err)
+		}
+		if _, err := s.LatestCheckResult(uuid.New()); !errors.Is(err, storage.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound on latest, got %v", err)
+		}
+	})
+
+	t.Run("No Results", func(t *testing.T) {
+		id, _ := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		if _, err := s.LatestCheckResult(id); !errors.Is(err, storage.ErrCheckResultNotFound) {
+			t.Errorf("Expected ErrCheckResultNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Sub Microsecond CheckedAt", func(t *testing.T) {
+		id, _ := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(id).Build()
+		result.CheckedAt = base.Add(1500 * time.Nanosecond)
+		if err := s.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := s.LatestCheckResult(id)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !latest.CheckedAt.Equal(result.CheckedAt) || !result.CheckedAt.Equal(base.Add(time.Microsecond)) {
+			t.Errorf("Expected CheckedAt truncated to %v, got saved %v and stored %v",
+				base.Add(time.Microsecond), result.CheckedAt, latest.CheckedAt)
+		}
+	})
+
+	t.Run("Deleted With Domain", func(t *testing.T) {
+		if err := s.DeleteDomain(domainID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.ListCheckResults(domainID, time.Time{}, time.Time{}, 1, 10); !errors.Is(err, storage.ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound, got %v", err)
+		}
+	})
+}
+
+func TestPostgresDNSChanges(t *testing.T) {
+	s, _ := newTestStorage(t)
+	domainID, err := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*models.DNSChangeEvent{
+		{DomainID: domainID, CurrentIPs: []string{"192.0.2.1"}, MatchesExpected: true, DetectedAt: base},
+		{
+			DomainID: domainID, PreviousIPs: []string{"192.0.2.1"}, CurrentIPs: []string{"192.0.2.2"},
+			ExpectedIPs: []string{"192.0.2.1"}, DetectedAt: base.Add(time.Minute),
+		},
+	}
+	for _, event := range events {
+		if err := s.SaveDNSChange(event); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	history, err := s.ListDNSChanges(domainID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(history, events) {
+		t.Errorf("Expected %+v, got %+v", events, history)
+	}
+
+	latest, err := s.LatestDNSChange(domainID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(latest, events[1]) {
+		t.Errorf("Expected latest %+v, got %+v", events[1], latest)
+	}
+	empty, _ := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if _, err := s.LatestDNSChange(empty); !errors.Is(err, storage.ErrDNSChangeNotFound) {
+		t.Errorf("Expected ErrDNSChangeNotFound, got %v", err)
+	}
+	if _, err := s.LatestDNSChange(uuid.New()); !errors.Is(err, storage.ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound, got %v", err)
+	}
+
+	orphan := &models.DNSChangeEvent{DomainID: uuid.New(), DetectedAt: base}
+	if err := s.SaveDNSChange(orphan); !errors.Is(err, storage.ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound, got %v", err)
+	}
+}
+
+// TestPostgresCursor testa a paginação por cursor de domínios e resultados
+func TestPostgresCursor(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	// Nomes repetidos exercitam o desempate pelo ID
+	for _, name := range []string{"bravo", "alpha", "charlie", "alpha"} {
+		if _, err := s.CreateDomain(helpers.NewTestDomainBuilder().WithName(name).Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Domains", func(t *testing.T) {
+		expected, err := s.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName, Order: models.SortDesc})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opts := models.CursorOptions{Limit: 3, SortBy: models.SortByName, Order: models.SortDesc}
+		var listed []*models.Domain
+		for {
+			page, err := s.ListDomainsCursor(opts)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			listed = append(listed, page.Domains...)
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+
+		if !reflect.DeepEqual(listed, expected.Domains) {
+			t.Errorf("Expected %v, got %v", expected.Domains, listed)
+		}
+
+		if _, err := s.ListDomainsCursor(models.CursorOptions{Cursor: opts.Cursor, Limit: 3}); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for a different sort, got %v", err)
+		}
+	})
+
+	t.Run("Check Results", func(t *testing.T) {
+		domainID, _ := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+		base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		for _, offset := range []time.Duration{time.Minute, 0, time.Minute, 2 * time.Minute, time.Minute, 2 * time.Minute} {
+			result := helpers.NewTestCheckResultBuilder().WithDomainID(domainID).WithCheckedAt(base.Add(offset)).Build()
+			if err := s.SaveCheckResult(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var listed []*models.CheckResult
+		cursor := ""
+		for {
+			page, err := s.ListCheckResultsCursor(domainID, time.Time{}, base.Add(2*time.Minute), cursor, 2)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			listed = append(listed, page.Results...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		if len(listed) != 4 {
+			t.Fatalf("Expected 4 results, got %d", len(listed))
+		}
+		for i := 1; i < len(listed); i++ {
+			if models.CompareCheckResults(listed[i-1], listed[i]) <= 0 {
+				t.Errorf("Expected newest first with ties by ID, got %v before %v", listed[i-1].ID, listed[i].ID)
+			}
+		}
+
+		// A paginação por offset e LatestCheckResult desempatam da mesma forma
+		for page := 1; page <= 2; page++ {
+			results, err := s.ListCheckResults(domainID, time.Time{}, base.Add(2*time.Minute), page, 2)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for i, result := range results {
+				if result.ID != listed[(page-1)*2+i].ID {
+					t.Errorf("Page %d differs from cursor pages at %d: got %v", page, i, result.ID)
+				}
+			}
+		}
+		all, err := s.ListCheckResults(domainID, time.Time{}, time.Time{}, 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if models.CompareCheckResults(all[0], all[1]) <= 0 {
+			t.Errorf("Expected ties broken by ID, got %v before %v", all[0].ID, all[1].ID)
+		}
+		if latest, err := s.LatestCheckResult(domainID); err != nil || latest.ID != all[0].ID {
+			t.Errorf("Expected latest %v, got %+v, %v", all[0].ID, latest, err)
+		}
+
+		if _, err := s.ListCheckResultsCursor(domainID, time.Time{}, time.Time{}, "", 0); !errors.Is(err, models.ErrInvalidLimit) {
+			t.Errorf("Expected ErrInvalidLimit, got %v", err)
+		}
+	})
+}
+
+func TestPostgresFilter(t *testing.T) {
+	s, _ := newTestStorage(t)
+	now := time.Now().Truncate(time.Microsecond)
+
+	domains := []struct {
+		domain    *models.Domain
+		status    int
+		checkedAt time.Time
+	}{
+		{helpers.NewTestDomainBuilder().WithName("shop").WithURL("https://Shop.example.com").WithTags("prod", "web").Build(), 200, now.Add(-time.Hour)},
+		{helpers.NewTestDomainBuilder().WithName("blog").WithURL("https://blog.example.org").WithTags("prod").Build(), 503, now.Add(-24 * time.Hour)},
+		{helpers.NewTestDomainBuilder().WithName("shop-staging").WithURL("https://staging.example.com").WithTags("staging").Build(), 0, time.Time{}},
+		{helpers.NewTestDomainBuilder().WithName("api").WithURL("https://api.example.net").Build(), 204, now},
+	}
+	for _, d := range domains {
+		id, err := s.CreateDomain(d.domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.checkedAt.IsZero() {
+			continue
+		}
+		result := helpers.NewTestCheckResultBuilder().WithDomainID(id).WithStatusCode(d.status).WithCheckedAt(d.checkedAt).Build()
+		if err := s.SaveCheckResult(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Um resultado mais antigo gravado depois não altera o status
+	old := helpers.NewTestCheckResultBuilder().WithDomainID(domains[0].domain.ID).WithStatusCode(500).WithCheckedAt(now.Add(-48 * time.Hour)).Build()
+	if err := s.SaveCheckResult(old); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Stored Fields", func(t *testing.T) {
+		shop, err := s.GetDomain(domains[0].domain.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(shop.Tags, []string{"prod", "web"}) || shop.Status != models.StatusUp ||
+			!shop.LastCheckedAt.Equal(now.Add(-time.Hour)) {
+			t.Errorf("Unexpected tags, status or last check: %v, %q, %v", shop.Tags, shop.Status, shop.LastCheckedAt)
+		}
+
+		staging, _ := s.GetDomain(domains[2].domain.ID)
+		if staging.Status != models.StatusUnknown || !staging.LastCheckedAt.IsZero() {
+			t.Errorf("Expected unknown status with no check, got %q at %v", staging.Status, staging.LastCheckedAt)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		filter   models.DomainFilter
+		expected []string
+	}{
+		{"Search", models.DomainFilter{Search: "SHOP"}, []string{"shop", "shop-staging"}},
+		{"Search URL", models.DomainFilter{Search: "example.org"}, []string{"blog"}},
+		{"Tag", models.DomainFilter{Tag: "prod"}, []string{"blog", "shop"}},
+		{"Status", models.DomainFilter{Status: models.StatusUnknown}, []string{"shop-staging"}},
+		{"Check Age", models.DomainFilter{MinCheckAge: 30 * time.Minute, MaxCheckAge: 2 * time.Hour}, []string{"shop"}},
+		{"Created Range", models.DomainFilter{CreatedAfter: domains[1].domain.CreatedAt, CreatedBefore: domains[3].domain.CreatedAt}, []string{"blog", "shop-staging"}},
+		{"Combined", models.DomainFilter{Tag: "prod", Status: models.StatusUp}, []string{"shop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.ListDomains(models.ListOptions{Page: 1, PageSize: 10, SortBy: models.SortByName, Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var names []string
+			for _, d := range result.Domains {
+				names = append(names, d.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) || result.Total != len(tt.expected) {
+				t.Errorf("Expected %v with total %d, got %v with total %d", tt.expected, len(tt.expected), names, result.Total)
+			}
+		})
+	}
+
+	t.Run("Cursor By Last Check", func(t *testing.T) {
+		opts := models.CursorOptions{Limit: 1, SortBy: models.SortByLastCheckedAt, Filter: models.DomainFilter{Search: "example"}}
+		var names []string
+		for {
+			page, err := s.ListDomainsCursor(opts)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, d := range page.Domains {
+				names = append(names, d.Name)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+
+		if expected := []string{"shop-staging", "blog", "shop", "api"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+}
+
+// TestPostgresUpdateKeepsCreatedAt testa que UpdateDomain ignora o CreatedAt
+// recebido e mantém o gravado na criação
+func TestPostgresUpdateKeepsCreatedAt(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	id, err := s.CreateDomain(helpers.NewTestDomainBuilder().Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.GetDomain(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := *created
+	changed.CreatedAt = created.CreatedAt.Add(-24 * time.Hour)
+	if err := s.UpdateDomain(&changed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, _ := s.GetDomain(id)
+	if !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Expected CreatedAt %v, got %v", created.CreatedAt, got.CreatedAt)
+	}
+}
+
+// TestPostgresDuplicateURL testa o índice único de URL na criação e na atualização
+func TestPostgresDuplicateURL(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	first := helpers.NewTestDomainBuilder().WithURL("https://example.com").Build()
+	if _, err := s.CreateDomain(first); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicate := helpers.NewTestDomainBuilder().WithURL("https://example.com").Build()
+	if _, err := s.CreateDomain(duplicate); !errors.Is(err, postgres.ErrDomainAlreadyExists) {
+		t.Fatalf("Expected ErrDomainAlreadyExists on create, got %v", err)
+	}
+
+	second := helpers.NewTestDomainBuilder().WithURL("https://example.org").Build()
+	if _, err := s.CreateDomain(second); err != nil {
+		t.Fatal(err)
+	}
+
+	second.URL = first.URL
+	if err := s.UpdateDomain(second); !errors.Is(err, postgres.ErrDomainAlreadyExists) {
+		t.Errorf("Expected ErrDomainAlreadyExists on update, got %v", err)
+	}
+
+	first.Name = "renamed"
+	if err := s.UpdateDomain(first); err != nil {
+		t.Errorf("Expected no error updating with the same URL, got %v", err)
+	}
+}
+
+// TestPostgresContext testa que as consultas respeitam o cancelamento de ctx
+func TestPostgresContext(t *testing.T) {
+	s, _ := newTestStorage(t)
+
+	d := helpers.NewTestDomainBuilder().Build()
+	if _, err := s.CreateDomain(d); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.CreateDomainContext(ctx, helpers.NewTestDomainBuilder().Build()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on create, got %v", err)
+	}
+	result := helpers.NewTestCheckResultBuilder().WithDomainID(d.ID).Build()
+	if err := s.SaveCheckResultContext(ctx, result); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on save, got %v", err)
+	}
+
+	page, err := s.ListDomains(models.ListOptions{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 {
+		t.Errorf("Expected only the original domain, got %d", page.Total)
+	}
+	if _, err := s.LatestCheckResult(d.ID); !errors.Is(err, postgres.ErrCheckResultNotFound) {
+		t.Errorf("Expected no check results, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration é uma versão do schema. As versões são aplicadas em ordem, cada
+// uma em sua transação, e registradas em schema_migrations.
+type migration struct {
+	version    int
+	statements []string
+}
+
+// migrations lista as versões do schema. Nunca altere uma versão já publicada;
+// acrescente uma nova.
+var migrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE domains (
+				id         TEXT PRIMARY KEY,
+				name       TEXT NOT NULL,
+				url        TEXT NOT NULL,
+				timeout    INTEGER NOT NULL DEFAULT 0,
+				interval   INTEGER NOT NULL DEFAULT 0,
+				ip         TEXT NOT NULL DEFAULT '',
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			)`,
+			`CREATE INDEX idx_domains_created_at ON domains (created_at, id)`,
+			`CREATE TABLE check_results (
+				id               TEXT PRIMARY KEY,
+				domain_id        TEXT NOT NULL REFERENCES domains (id) ON DELETE CASCADE,
+				status_code      INTEGER NOT NULL DEFAULT 0,
+				response_time_ms INTEGER NOT NULL DEFAULT 0,
+				error            TEXT NOT NULL DEFAULT '',
+				error_kind       TEXT NOT NULL DEFAULT '',
+				redirect_url     TEXT NOT NULL DEFAULT '',
+				redirect_count   INTEGER NOT NULL DEFAULT 0,
+				checked_at       INTEGER NOT NULL,
+				content_length   INTEGER NOT NULL DEFAULT 0,
+				server           TEXT NOT NULL DEFAULT '',
+				resolved_ip      TEXT NOT NULL DEFAULT '',
+				resolved_ips     TEXT,
+				unexpected_ip    INTEGER NOT NULL DEFAULT 0,
+				redirect_chain   TEXT,
+				tls              TEXT
+			)`,
+			`CREATE INDEX idx_check_results_domain_checked_at ON check_results (domain_id, checked_at)`,
+			`CREATE TABLE dns_changes (
+				id               TEXT PRIMARY KEY,
+				domain_id        TEXT NOT NULL REFERENCES domains (id) ON DELETE CASCADE,
+				previous_ips     TEXT,
+				current_ips      TEXT,
+				expected_ips     TEXT,
+				matches_expected INTEGER NOT NULL DEFAULT 0,
+				detected_at      INTEGER NOT NULL
+			)`,
+			`CREATE INDEX idx_dns_changes_domain_detected_at ON dns_changes (domain_id, detected_at)`,
+		},
+	},
+	{
+		// Tags e o status da verificação mais recente, para filtrar a listagem
+		version: 2,
+		statements: []string{
+			`ALTER TABLE domains ADD COLUMN tags TEXT`,
+			`ALTER TABLE domains ADD COLUMN status TEXT NOT NULL DEFAULT 'unknown'`,
+			`ALTER TABLE domains ADD COLUMN last_checked_at INTEGER`,
+			// Preenche a partir dos resultados já gravados, com a regra de
+			// models.CheckResult.Status
+			`UPDATE domains SET (status, last_checked_at) = (
+				SELECT CASE WHEN error = '' AND status_code BETWEEN 200 AND 399 THEN 'up' ELSE 'down' END, checked_at
+				FROM check_results WHERE domain_id = domains.id
+				ORDER BY checked_at DESC, rowid DESC LIMIT 1
+			) WHERE EXISTS (SELECT 1 FROM check_results WHERE domain_id = domains.id)`,
+			`CREATE INDEX idx_domains_status ON domains (status)`,
+			`CREATE INDEX idx_domains_last_checked_at ON domains (last_checked_at)`,
+		},
+	},
+	{
+		// Falha se o banco já tiver URLs repetidas, que devem ser removidas
+		// antes da atualização
+		version: 3,
+		statements: []string{
+			`CREATE UNIQUE INDEX idx_domains_url ON domains (url)`,
+		},
+	},
+	{
+		// Duração de cada etapa da verificação; nula nos resultados anteriores
+		version: 4,
+		statements: []string{
+			`ALTER TABLE check_results ADD COLUMN timings TEXT`,
+		},
+	},
+	{
+		// Asserções de conteúdo por domínio e as falhas de cada verificação
+		version: 5,
+		statements: []string{
+			`ALTER TABLE domains ADD COLUMN assertions TEXT`,
+			`ALTER TABLE check_results ADD COLUMN assertion_failures TEXT`,
+		},
+	},
+	{
+		// Índice na ordem das listagens de resultados, com o ID como desempate;
+		// substitui o índice por (domain_id, checked_at), que fica redundante
+		version: 6,
+		statements: []string{
+			`CREATE INDEX idx_check_results_domain_checked_at_id ON check_results (domain_id, checked_at DESC, id DESC)`,
+			`DROP INDEX IF EXISTS idx_check_results_domain_checked_at`,
+		},
+	},
+	{
+		// Status aceito por Assertions.StatusCodes; falso nos resultados anteriores
+		version: 7,
+		statements: []string{
+			`ALTER TABLE check_results ADD COLUMN expected_status INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+}
+
+// migrate aplica as versões do schema ainda não registradas no banco
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("sqlite: create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("sqlite: read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("sqlite: migration %d: %w", m.version, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range m.statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+		m.version, time.Now().UnixNano()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,772 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luizhreis/domain-watcher/internal/models"
+	"github.com/luizhreis/domain-watcher/internal/storage/errs"
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrDomainNotFound é retornado quando um domínio não é encontrado
+	ErrDomainNotFound = errs.ErrDomainNotFound
+	// ErrCheckResultNotFound é retornado quando o domínio ainda não tem resultados
+	ErrCheckResultNotFound = errs.ErrCheckResultNotFound
+	// ErrDNSChangeNotFound é retornado quando o domínio ainda não tem mudanças de IP
+	ErrDNSChangeNotFound = errs.ErrDNSChangeNotFound
+	// ErrDomainAlreadyExists é retornado quando outro domínio já usa a mesma URL
+	ErrDomainAlreadyExists = errs.ErrDomainAlreadyExists
+	// ErrEmptyPath é retornado quando o caminho do banco não é informado
+	ErrEmptyPath = errors.New("sqlite: empty database path")
+)
+
+// driverName é o driver sqlite3 com a função fold, que converte para
+// minúsculas como strings.ToLower. O lower() do SQLite só converte ASCII.
+const driverName = "sqlite3_domain_watcher"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("fold", strings.ToLower, true)
+		},
+	})
+}
+
+// SQLiteStorage é uma implementação do Storage em um arquivo SQLite.
+// Os horários são gravados em nanossegundos UTC para permitir comparação e
+// ordenação diretamente no banco.
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteStorage abre (ou cria) o banco em path e aplica as migrações pendentes
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	if path == "" {
+		return nil, ErrEmptyPath
+	}
+
+	// O caminho é escapado para que ?, # e % façam parte do nome do arquivo
+	dsn := &url.URL{
+		Scheme:   "file",
+		Path:     path,
+		RawQuery: "_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL",
+	}
+	db, err := sql.Open(driverName, dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// O SQLite aceita um escritor por vez; uma única conexão evita SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+// Close fecha o banco
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// CreateDomain cria um novo domínio no storage
+func (s *SQLiteStorage) CreateDomain(domain *models.Domain) (uuid.UUID, error) {
+	return s.CreateDomainContext(context.Background(), domain)
+}
+
+func (s *SQLiteStorage) CreateDomainContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error) {
+	domain.ID = uuid.New()
+
+	// Define timestamps
+	now := time.Now()
+	domain.CreatedAt = now
+	domain.UpdatedAt = now
+
+	// O status vem das verificações gravadas depois
+	domain.Status = models.StatusUnknown
+	domain.LastCheckedAt = time.Time{}
+
+	tags, err := json.Marshal(domain.Tags)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	assertions, err := json.Marshal(domain.Assertions)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO domains (id, name, url, timeout, interval, ip, tags, assertions, status,
+		created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		domain.ID.String(), domain.Name, domain.URL, domain.Timeout, domain.Interval, domain.IP,
+		string(tags), string(assertions), string(domain.Status), toUnix(domain.CreatedAt), toUnix(domain.UpdatedAt))
+	if err != nil {
+		return uuid.Nil, uniqueError(err)
+	}
+
+	return domain.ID, nil
+}
+
+// GetDomain busca um domínio pelo ID
+func (s *SQLiteStorage) GetDomain(id uuid.UUID) (*models.Domain, error) {
+	return s.GetDomainContext(context.Background(), id)
+}
+
+func (s *SQLiteStorage) GetDomainContext(ctx context.Context, id uuid.UUID) (*models.Domain, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+domainColumns+` FROM domains WHERE id = ?`, id.String())
+
+	domain, err := scanDomain(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDomainNotFound
+	}
+	return domain, err
+}
+
+// ListDomains retorna uma página de domínios na ordem definida por opts
+func (s *SQLiteStorage) ListDomains(opts models.ListOptions) (*models.DomainPage, error) {
+	return s.ListDomainsContext(context.Background(), opts)
+}
+
+func (s *SQLiteStorage) ListDomainsContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error) {
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	where, args := filterDomains(opts.Filter, time.Now())
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM domains`+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	domains, err := s.queryDomains(ctx, `SELECT `+domainColumns+` FROM domains`+where+`
+		ORDER BY `+orderBy(opts)+` LIMIT ? OFFSET ?`, append(args, opts.PageSize, opts.Offset())...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DomainPage{
+		Domains:  domains,
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// ListDomainsCursor retorna os domínios após opts.Cursor na ordem definida por opts
+func (s *SQLiteStorage) ListDomainsCursor(opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	return s.ListDomainsCursorContext(context.Background(), opts)
+}
+
+func (s *SQLiteStorage) ListDomainsCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	listOpts, cursor, err := opts.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := filterDomains(listOpts.Filter, time.Now())
+	if cursor != nil {
+		condition, conditionArgs := afterDomain(listOpts, cursor)
+		if where == "" {
+			where = ` WHERE ` + condition
+		} else {
+			where += ` AND ` + condition
+		}
+		args = append(args, conditionArgs...)
+	}
+
+	query := `SELECT ` + domainColumns + ` FROM domains` + where
+
+	// Um domínio além do limite indica que há próxima página
+	query += ` ORDER BY ` + orderBy(listOpts) + ` LIMIT ?`
+	args = append(args, opts.Limit+1)
+
+	domains, err := s.queryDomains(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewDomainCursorPage(domains, listOpts, opts.Limit), nil
+}
+
+// UpdateDomain atualiza um domínio existente
+func (s *SQLiteStorage) UpdateDomain(domain *models.Domain) error {
+	return s.UpdateDomainContext(context.Background(), domain)
+}
+
+func (s *SQLiteStorage) UpdateDomainContext(ctx context.Context, domain *models.Domain) error {
+	domain.UpdatedAt = time.Now()
+
+	tags, err := json.Marshal(domain.Tags)
+	if err != nil {
+		return err
+	}
+	assertions, err := json.Marshal(domain.Assertions)
+	if err != nil {
+		return err
+	}
+
+	// status e last_checked_at são mantidos por SaveCheckResult
+	res, err := s.db.ExecContext(ctx, `UPDATE domains SET name = ?, url = ?, timeout = ?, interval = ?, ip = ?, tags = ?,
+		assertions = ?, updated_at = ? WHERE id = ?`,
+		domain.Name, domain.URL, domain.Timeout, domain.Interval, domain.IP, string(tags), string(assertions),
+		toUnix(domain.UpdatedAt), domain.ID.String())
+	if err != nil {
+		return uniqueError(err)
+	}
+
+	return requireRow(res)
+}
+
+// DeleteDomain remove um domínio junto com seus resultados e mudanças de IP
+func (s *SQLiteStorage) DeleteDomain(id uuid.UUID) error {
+	return s.DeleteDomainContext(context.Background(), id)
+}
+
+func (s *SQLiteStorage) DeleteDomainContext(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM domains WHERE id = ?`, id.String())
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res)
+}
+
+// SaveDNSChange registra uma mudança de IP no histórico do domínio
+func (s *SQLiteStorage) SaveDNSChange(event *models.DNSChangeEvent) error {
+	return s.SaveDNSChangeContext(context.Background(), event)
+}
+
+func (s *SQLiteStorage) SaveDNSChangeContext(ctx context.Context, event *models.DNSChangeEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	previous, err := json.Marshal(event.PreviousIPs)
+	if err != nil {
+		return err
+	}
+	current, err := json.Marshal(event.CurrentIPs)
+	if err != nil {
+		return err
+	}
+	expected, err := json.Marshal(event.ExpectedIPs)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO dns_changes
+		(id, domain_id, previous_ips, current_ips, expected_ips, matches_expected, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		event.ID.String(), event.DomainID.String(), string(previous), string(current), string(expected),
+		event.MatchesExpected, toUnix(event.DetectedAt))
+	return foreignKeyError(err)
+}
+
+// ListDNSChanges retorna o histórico de mudanças de IP do domínio, do mais antigo ao mais recente
+func (s *SQLiteStorage) ListDNSChanges(domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	return s.ListDNSChangesContext(context.Background(), domainID)
+}
+
+func (s *SQLiteStorage) ListDNSChangesContext(ctx context.Context, domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	if err := s.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+dnsChangeColumns+`
+		FROM dns_changes WHERE domain_id = ? ORDER BY detected_at, rowid`, domainID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.DNSChangeEvent{}
+	for rows.Next() {
+		event, err := scanDNSChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// LatestDNSChange retorna a mudança de IP mais recente do domínio
+func (s *SQLiteStorage) LatestDNSChange(domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	return s.LatestDNSChangeContext(context.Background(), domainID)
+}
+
+func (s *SQLiteStorage) LatestDNSChangeContext(ctx context.Context, domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	if err := s.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+dnsChangeColumns+` FROM dns_changes
+		WHERE domain_id = ? ORDER BY detected_at DESC, rowid DESC LIMIT 1`, domainID.String())
+
+	event, err := scanDNSChange(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDNSChangeNotFound
+	}
+	return event, err
+}
+
+// SaveCheckResult registra o resultado de uma verificação do domínio
+func (s *SQLiteStorage) SaveCheckResult(result *models.CheckResult) error {
+	return s.SaveCheckResultContext(context.Background(), result)
+}
+
+func (s *SQLiteStorage) SaveCheckResultContext(ctx context.Context, result *models.CheckResult) error {
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+	}
+	if result.CheckedAt.IsZero() {
+		result.CheckedAt = time.Now()
+	}
+
+	resolvedIPs, err := json.Marshal(result.ResolvedIPs)
+	if err != nil {
+		return err
+	}
+	redirectChain, err := json.Marshal(result.RedirectChain)
+	if err != nil {
+		return err
+	}
+	tlsInfo, err := json.Marshal(result.TLS)
+	if err != nil {
+		return err
+	}
+	timings, err := json.Marshal(result.Timings)
+	if err != nil {
+		return err
+	}
+	assertionFailures, err := json.Marshal(result.AssertionFailures)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO check_results (`+checkResultColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.ID.String(), result.DomainID.String(), result.StatusCode, result.ResponseTime,
+		result.Error, string(result.ErrorKind), result.RedirectURL, result.RedirectCount,
+		toUnix(result.CheckedAt), result.ContentLength, result.Server, result.ResolvedIP,
+		string(resolvedIPs), result.UnexpectedIP, string(redirectChain), string(tlsInfo), string(timings),
+		string(assertionFailures), result.ExpectedStatus)
+	if err != nil {
+		return foreignKeyError(err)
+	}
+
+	// O status do domínio acompanha o resultado mais recente
+	checkedAt := toUnix(result.CheckedAt)
+	_, err = tx.ExecContext(ctx, `UPDATE domains SET status = ?, last_checked_at = ?
+		WHERE id = ? AND (last_checked_at IS NULL OR last_checked_at <= ?)`,
+		string(result.Status()), checkedAt, result.DomainID.String(), checkedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListCheckResults retorna os resultados do domínio no intervalo [from, to),
+// do mais recente ao mais antigo, com paginação
+func (s *SQLiteStorage) ListCheckResults(domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	return s.ListCheckResultsContext(context.Background(), domainID, from, to, page, pageSize)
+}
+
+func (s *SQLiteStorage) ListCheckResultsContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("page and pageSize must be greater than 0")
+	}
+
+	if err := s.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	query, args := checkResultsInRange(domainID, from, to)
+	query += ` ORDER BY checked_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	return s.queryCheckResults(ctx, query, args...)
+}
+
+// ListCheckResultsCursor retorna os resultados do domínio no intervalo
+// [from, to) após cursor, do mais recente ao mais antigo
+func (s *SQLiteStorage) ListCheckResultsCursor(domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	return s.ListCheckResultsCursorContext(context.Background(), domainID, from, to, cursor, limit)
+}
+
+func (s *SQLiteStorage) ListCheckResultsCursorContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	if limit < 1 {
+		return nil, models.ErrInvalidLimit
+	}
+
+	var after *models.CheckResultCursor
+	if cursor != "" {
+		var err error
+		if after, err = models.DecodeCheckResultCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	query, args := checkResultsInRange(domainID, from, to)
+	if after != nil {
+		query += ` AND (checked_at < ? OR (checked_at = ? AND id < ?))`
+		args = append(args, toUnix(after.CheckedAt), toUnix(after.CheckedAt), after.ID.String())
+	}
+
+	// Um resultado além do limite indica que há próxima página
+	query += ` ORDER BY checked_at DESC, id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	results, err := s.queryCheckResults(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCheckResultCursorPage(results, limit), nil
+}
+
+// LatestCheckResult retorna o resultado mais recente do domínio
+func (s *SQLiteStorage) LatestCheckResult(domainID uuid.UUID) (*models.CheckResult, error) {
+	return s.LatestCheckResultContext(context.Background(), domainID)
+}
+
+func (s *SQLiteStorage) LatestCheckResultContext(ctx context.Context, domainID uuid.UUID) (*models.CheckResult, error) {
+	if err := s.requireDomain(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+checkResultColumns+` FROM check_results
+		WHERE domain_id = ? ORDER BY checked_at DESC, id DESC LIMIT 1`, domainID.String())
+
+	result, err := scanCheckResult(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCheckResultNotFound
+	}
+	return result, err
+}
+
+const domainColumns = `id, name, url, timeout, interval, ip, tags, assertions, status, created_at, updated_at,
+	last_checked_at`
+
+const checkResultColumns = `id, domain_id, status_code, response_time_ms, error, error_kind,
+	redirect_url, redirect_count, checked_at, content_length, server, resolved_ip,
+	resolved_ips, unexpected_ip, redirect_chain, tls, timings, assertion_failures, expected_status`
+
+// scanner é satisfeito por *sql.Row e *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// sortColumns mapeia os campos de ordenação para as colunas da tabela domains
+var sortColumns = map[models.SortField]string{
+	models.SortByCreatedAt: "created_at",
+	models.SortByUpdatedAt: "updated_at",
+	models.SortByName:      "name",
+	models.SortByURL:       "url",
+	models.SortByStatus:    "status",
+	// Domínios nunca verificados ficam antes de todos, como o time.Time zero
+	models.SortByLastCheckedAt: "COALESCE(last_checked_at, " + strconv.FormatInt(math.MinInt64, 10) + ")",
+}
+
+// orderBy monta a cláusula ORDER BY de opts, já validado, com o ID como desempate
+func orderBy(opts models.ListOptions) string {
+	direction := " ASC"
+	if opts.Order == models.SortDesc {
+		direction = " DESC"
+	}
+	return sortColumns[opts.SortBy] + direction + ", id" + direction
+}
+
+// afterDomain monta a condição que seleciona os domínios após cursor na ordem de opts
+func afterDomain(opts models.ListOptions, cursor *models.DomainCursor) (string, []any) {
+	op := " > "
+	if opts.Order == models.SortDesc {
+		op = " < "
+	}
+
+	var value any = cursor.Value
+	switch opts.SortBy {
+	case models.SortByCreatedAt, models.SortByUpdatedAt:
+		value = toUnix(cursor.Time)
+	case models.SortByLastCheckedAt:
+		value = int64(math.MinInt64)
+		if !cursor.Time.IsZero() {
+			value = toUnix(cursor.Time)
+		}
+	}
+
+	column := sortColumns[opts.SortBy]
+	return "(" + column + op + "? OR (" + column + " = ? AND id" + op + "?))",
+		[]any{value, value, cursor.ID.String()}
+}
+
+// filterDomains monta a cláusula WHERE do filtro, vazia se não há restrições
+func filterDomains(filter models.DomainFilter, now time.Time) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		conditions = append(conditions, `(instr(fold(name), ?) > 0 OR instr(fold(url), ?) > 0)`)
+		args = append(args, search, search)
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM json_each(domains.tags) WHERE value = ?)`)
+		args = append(args, filter.Tag)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, `status = ?`)
+		args = append(args, string(filter.Status))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, toUnix(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, `created_at < ?`)
+		args = append(args, toUnix(filter.CreatedBefore))
+	}
+
+	// last_checked_at nulo nunca satisfaz as comparações
+	from, to := filter.LastCheckRange(now)
+	if !from.IsZero() {
+		conditions = append(conditions, `last_checked_at >= ?`)
+		args = append(args, toUnix(from))
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, `last_checked_at <= ?`)
+		args = append(args, toUnix(to))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return ` WHERE ` + strings.Join(conditions, ` AND `), args
+}
+
+// checkResultsInRange monta a consulta dos resultados do domínio no intervalo [from, to)
+func checkResultsInRange(domainID uuid.UUID, from, to time.Time) (string, []any) {
+	query := `SELECT ` + checkResultColumns + ` FROM check_results WHERE domain_id = ?`
+	args := []any{domainID.String()}
+	if !from.IsZero() {
+		query += ` AND checked_at >= ?`
+		args = append(args, toUnix(from))
+	}
+	if !to.IsZero() {
+		query += ` AND checked_at < ?`
+		args = append(args, toUnix(to))
+	}
+	return query, args
+}
+
+func (s *SQLiteStorage) queryDomains(ctx context.Context, query string, args ...any) ([]*models.Domain, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := []*models.Domain{}
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, rows.Err()
+}
+
+func (s *SQLiteStorage) queryCheckResults(ctx context.Context, query string, args ...any) ([]*models.CheckResult, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*models.CheckResult{}
+	for rows.Next() {
+		result, err := scanCheckResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func scanDomain(row scanner) (*models.Domain, error) {
+	var (
+		domain               models.Domain
+		tags, assertions     sql.NullString
+		status               string
+		createdAt, updatedAt int64
+		lastCheckedAt        sql.NullInt64
+	)
+	if err := row.Scan(&domain.ID, &domain.Name, &domain.URL, &domain.Timeout, &domain.Interval,
+		&domain.IP, &tags, &assertions, &status, &createdAt, &updatedAt, &lastCheckedAt); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(tags, &domain.Tags); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(assertions, &domain.Assertions); err != nil {
+		return nil, err
+	}
+	domain.Status = models.DomainStatus(status)
+	domain.CreatedAt = fromUnix(createdAt)
+	domain.UpdatedAt = fromUnix(updatedAt)
+	if lastCheckedAt.Valid {
+		domain.LastCheckedAt = fromUnix(lastCheckedAt.Int64)
+	}
+	return &domain, nil
+}
+
+const dnsChangeColumns = `id, domain_id, previous_ips, current_ips, expected_ips, matches_expected, detected_at`
+
+func scanDNSChange(row scanner) (*models.DNSChangeEvent, error) {
+	var (
+		event                       models.DNSChangeEvent
+		previous, current, expected sql.NullString
+		detectedAt                  int64
+	)
+	if err := row.Scan(&event.ID, &event.DomainID, &previous, &current, &expected,
+		&event.MatchesExpected, &detectedAt); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(previous, &event.PreviousIPs); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(current, &event.CurrentIPs); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(expected, &event.ExpectedIPs); err != nil {
+		return nil, err
+	}
+	event.DetectedAt = fromUnix(detectedAt)
+	return &event, nil
+}
+
+func scanCheckResult(row scanner) (*models.CheckResult, error) {
+	var (
+		result                                     models.CheckResult
+		errorKind                                  string
+		checkedAt                                  int64
+		resolvedIPs, redirectChain, tlsJS, timings sql.NullString
+		assertionFailures                          sql.NullString
+	)
+	if err := row.Scan(&result.ID, &result.DomainID, &result.StatusCode, &result.ResponseTime,
+		&result.Error, &errorKind, &result.RedirectURL, &result.RedirectCount, &checkedAt,
+		&result.ContentLength, &result.Server, &result.ResolvedIP, &resolvedIPs,
+		&result.UnexpectedIP, &redirectChain, &tlsJS, &timings, &assertionFailures,
+		&result.ExpectedStatus); err != nil {
+		return nil, err
+	}
+
+	result.ErrorKind = models.ErrorKind(errorKind)
+	result.CheckedAt = fromUnix(checkedAt)
+
+	if err := unmarshalJSON(resolvedIPs, &result.ResolvedIPs); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(redirectChain, &result.RedirectChain); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(tlsJS, &result.TLS); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(timings, &result.Timings); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(assertionFailures, &result.AssertionFailures); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// requireDomain retorna ErrDomainNotFound quando o domínio não existe
+func (s *SQLiteStorage) requireDomain(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM domains WHERE id = ?)`, id.String()).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDomainNotFound
+	}
+	return nil
+}
+
+// requireRow retorna ErrDomainNotFound quando a operação não afetou nenhuma linha
+func requireRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrDomainNotFound
+	}
+	return nil
+}
+
+// uniqueError converte a violação do índice único de URL em ErrDomainAlreadyExists
+func uniqueError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return ErrDomainAlreadyExists
+	}
+	return err
+}
+
+// foreignKeyError converte a violação da chave estrangeira domain_id em ErrDomainNotFound
+func foreignKeyError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+		return ErrDomainNotFound
+	}
+	return err
+}
+
+func unmarshalJSON(data sql.NullString, v any) error {
+	if !data.Valid || data.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(data.String), v)
+}
+
+func toUnix(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+func fromUnix(n int64) time.Time {
+	return time.Unix(0, n).UTC()
+}
@@ -1,13 +1,17 @@
 package helpers
 
 import (
+	"context"
+
 	"github.com/luizhreis/domain-watcher/internal/dns"
 )
 
 // MockDNS - Mock centralizado para testes de integração
 type MockDNS struct {
-	resolveFunc func(domain string) (string, error)
-	callHistory []string
+	resolveFunc    func(domain string) (string, error)
+	resolveAllFunc func(domain string) ([]string, error)
+	lookupFunc     func(domain string) (*dns.Records, error)
+	callHistory    []string
 }
 
 // Compile-time check para garantir que implementa a interface
@@ -28,6 +32,75 @@ func (m *MockDNS) Resolve(domain string) (string, error) {
 	return "192.168.1.1", nil
 }
 
+func (m *MockDNS) ResolveAll(domain string) ([]string, error) {
+	if m.resolveAllFunc != nil {
+		m.callHistory = append(m.callHistory, domain)
+		return m.resolveAllFunc(domain)
+	}
+
+	ip, err := m.Resolve(domain)
+	if err != nil {
+		return nil, err
+	}
+	return []string{ip}, nil
+}
+
+func (m *MockDNS) SetResolveAllFunc(f func(domain string) ([]string, error)) {
+	m.resolveAllFunc = f
+}
+
+func (m *MockDNS) Lookup(domain string) (*dns.Records, error) {
+	m.callHistory = append(m.callHistory, domain)
+
+	if m.lookupFunc != nil {
+		return m.lookupFunc(domain)
+	}
+	return &dns.Records{
+		Name: domain,
+		A:    []dns.IPRecord{{IP: "192.168.1.1", TTL: 300}},
+	}, nil
+}
+
+// LookupType usa a mesma resposta de Lookup, qualquer que seja o tipo
+func (m *MockDNS) LookupType(domain string, _ dns.RecordType) (*dns.Records, error) {
+	return m.Lookup(domain)
+}
+
+// As variantes com Context falham com o erro de ctx quando ele já terminou e,
+// caso contrário, se comportam como as versões sem Context
+
+func (m *MockDNS) ResolveContext(ctx context.Context, domain string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return m.Resolve(domain)
+}
+
+func (m *MockDNS) ResolveAllContext(ctx context.Context, domain string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ResolveAll(domain)
+}
+
+func (m *MockDNS) LookupContext(ctx context.Context, domain string) (*dns.Records, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Lookup(domain)
+}
+
+func (m *MockDNS) LookupTypeContext(ctx context.Context, domain string, recordType dns.RecordType) (*dns.Records, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LookupType(domain, recordType)
+}
+
+func (m *MockDNS) SetLookupFunc(f func(domain string) (*dns.Records, error)) {
+	m.lookupFunc = f
+}
+
 func (m *MockDNS) SetResolveFunc(f func(domain string) (string, error)) {
 	m.resolveFunc = f
 }
@@ -1,7 +1,12 @@
 package helpers
 
 import (
+	"context"
 	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/models"
@@ -9,8 +14,12 @@ import (
 )
 
 type MockStorage struct {
+	mu sync.Mutex
+
 	// Add fields as needed for your mock storage implementation
 	domains                 map[uuid.UUID]*models.Domain
+	dnsChanges              map[uuid.UUID][]*models.DNSChangeEvent
+	checkResults            map[uuid.UUID][]*models.CheckResult
 	callHistory             []string
 	createDomainShouldError bool
 	getDomainShouldError    bool
@@ -23,26 +32,40 @@ var _ storage.Storage = (*MockStorage)(nil)
 
 func NewMockStorage() *MockStorage {
 	return &MockStorage{
-		domains:     make(map[uuid.UUID]*models.Domain),
-		callHistory: []string{},
+		domains:      make(map[uuid.UUID]*models.Domain),
+		dnsChanges:   make(map[uuid.UUID][]*models.DNSChangeEvent),
+		checkResults: make(map[uuid.UUID][]*models.CheckResult),
+		callHistory:  []string{},
 	}
 }
 
 func (m *MockStorage) CreateDomain(domain *models.Domain) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "CreateDomain")
 
 	if m.createDomainShouldError {
 		return uuid.Nil, errors.New("simulated CreateDomain error")
 	}
 
+	if m.urlTaken(domain.URL, uuid.Nil) {
+		return uuid.Nil, storage.ErrDomainAlreadyExists
+	}
+
 	if domain.ID == uuid.Nil {
 		domain.ID = uuid.New()
 	}
+	domain.Status = models.StatusUnknown
+	domain.LastCheckedAt = time.Time{}
 	m.domains[domain.ID] = domain
 	return domain.ID, nil
 }
 
 func (m *MockStorage) GetDomain(id uuid.UUID) (*models.Domain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "GetDomain")
 
 	if m.getDomainShouldError {
@@ -57,6 +80,9 @@ func (m *MockStorage) GetDomain(id uuid.UUID) (*models.Domain, error) {
 }
 
 func (m *MockStorage) GetAllDomains() ([]*models.Domain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "GetAllDomains")
 	domains := make([]*models.Domain, 0, len(m.domains))
 	for _, domain := range m.domains {
@@ -65,54 +91,111 @@ func (m *MockStorage) GetAllDomains() ([]*models.Domain, error) {
 	return domains, nil
 }
 
-func (m *MockStorage) ListDomains(page, pageSize int) ([]*models.Domain, error) {
+func (m *MockStorage) ListDomains(opts models.ListOptions) (*models.DomainPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "ListDomains")
 
 	if m.listDomainsShouldError {
 		return nil, errors.New("mock list domains error")
 	}
 
-	if page < 1 || pageSize < 1 {
-		return nil, errors.New("page and pageSize must be greater than 0")
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Converte map para slice para permitir paginação
+	// Converte map para slice, filtra e ordena como os backends reais
+	now := time.Now()
 	allDomains := make([]*models.Domain, 0, len(m.domains))
 	for _, domain := range m.domains {
-		allDomains = append(allDomains, domain)
+		if opts.Filter.Matches(domain, now) {
+			allDomains = append(allDomains, domain)
+		}
 	}
+	slices.SortFunc(allDomains, opts.Compare)
 
 	// Calcula offset
-	startIndex := (page - 1) * pageSize
-	endIndex := startIndex + pageSize
+	startIndex := min(opts.Offset(), len(allDomains))
+	endIndex := min(startIndex+opts.PageSize, len(allDomains))
+
+	return &models.DomainPage{
+		Domains:  allDomains[startIndex:endIndex],
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+		Total:    len(allDomains),
+	}, nil
+}
+
+func (m *MockStorage) ListDomainsCursor(opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// Verifica limites
-	if startIndex >= len(allDomains) {
-		return []*models.Domain{}, nil // Página vazia, mas não é erro
+	m.callHistory = append(m.callHistory, "ListDomainsCursor")
+
+	if m.listDomainsShouldError {
+		return nil, errors.New("mock list domains error")
+	}
+
+	listOpts, cursor, err := opts.Decode()
+	if err != nil {
+		return nil, err
 	}
 
-	if endIndex > len(allDomains) {
-		endIndex = len(allDomains)
+	now := time.Now()
+	var domains []*models.Domain
+	for _, domain := range m.domains {
+		if !listOpts.Filter.Matches(domain, now) {
+			continue
+		}
+		if cursor == nil || listOpts.Compare(cursor.Domain(), domain) < 0 {
+			domains = append(domains, domain)
+		}
 	}
+	slices.SortFunc(domains, listOpts.Compare)
 
-	return allDomains[startIndex:endIndex], nil
+	return models.NewDomainCursorPage(domains[:min(len(domains), opts.Limit+1)], listOpts, opts.Limit), nil
+}
+
+// urlTaken informa se outro domínio, diferente de except, já usa url
+func (m *MockStorage) urlTaken(url string, except uuid.UUID) bool {
+	for id, domain := range m.domains {
+		if id != except && domain.URL == url {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *MockStorage) UpdateDomain(domain *models.Domain) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "UpdateDomain")
 
 	if m.updateDomainShouldError {
 		return errors.New("mock update domain error")
 	}
 
-	if _, exists := m.domains[domain.ID]; !exists {
+	stored, exists := m.domains[domain.ID]
+	if !exists {
 		return storage.ErrDomainNotFound
 	}
+	if m.urlTaken(domain.URL, domain.ID) {
+		return storage.ErrDomainAlreadyExists
+	}
+	domain.CreatedAt = stored.CreatedAt
+	domain.Status = stored.Status
+	domain.LastCheckedAt = stored.LastCheckedAt
 	m.domains[domain.ID] = domain
 	return nil
 }
 
 func (m *MockStorage) DeleteDomain(id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = append(m.callHistory, "DeleteDomain")
 
 	if m.deleteDomainShouldError {
@@ -126,40 +209,322 @@ func (m *MockStorage) DeleteDomain(id uuid.UUID) error {
 	return nil
 }
 
+func (m *MockStorage) SaveDNSChange(event *models.DNSChangeEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "SaveDNSChange")
+
+	if _, exists := m.domains[event.DomainID]; !exists {
+		return storage.ErrDomainNotFound
+	}
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	m.dnsChanges[event.DomainID] = append(m.dnsChanges[event.DomainID], event)
+	return nil
+}
+
+func (m *MockStorage) ListDNSChanges(domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "ListDNSChanges")
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, storage.ErrDomainNotFound
+	}
+	return append([]*models.DNSChangeEvent(nil), m.dnsChanges[domainID]...), nil
+}
+
+func (m *MockStorage) LatestDNSChange(domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "LatestDNSChange")
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, storage.ErrDomainNotFound
+	}
+	events := m.dnsChanges[domainID]
+	if len(events) == 0 {
+		return nil, storage.ErrDNSChangeNotFound
+	}
+	return events[len(events)-1], nil
+}
+
+func (m *MockStorage) SaveCheckResult(result *models.CheckResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "SaveCheckResult")
+
+	domain, exists := m.domains[result.DomainID]
+	if !exists {
+		return storage.ErrDomainNotFound
+	}
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+	}
+	m.checkResults[result.DomainID] = append(m.checkResults[result.DomainID], result)
+	// Troca por uma cópia para não alterar o valor que quem chamou ainda usa
+	if !result.CheckedAt.Before(domain.LastCheckedAt) {
+		updated := *domain
+		updated.Status = result.Status()
+		updated.LastCheckedAt = result.CheckedAt
+		m.domains[result.DomainID] = &updated
+	}
+	return nil
+}
+
+func (m *MockStorage) ListCheckResults(domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "ListCheckResults")
+
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("page and pageSize must be greater than 0")
+	}
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, storage.ErrDomainNotFound
+	}
+
+	var results []*models.CheckResult
+	for _, result := range m.checkResults[domainID] {
+		if (from.IsZero() || !result.CheckedAt.Before(from)) && (to.IsZero() || result.CheckedAt.Before(to)) {
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CheckedAt.After(results[j].CheckedAt)
+	})
+
+	startIndex := (page - 1) * pageSize
+	if startIndex >= len(results) {
+		return []*models.CheckResult{}, nil
+	}
+	return results[startIndex:min(startIndex+pageSize, len(results))], nil
+}
+
+func (m *MockStorage) ListCheckResultsCursor(domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "ListCheckResultsCursor")
+
+	if limit < 1 {
+		return nil, models.ErrInvalidLimit
+	}
+	var after *models.CheckResultCursor
+	if cursor != "" {
+		var err error
+		if after, err = models.DecodeCheckResultCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, storage.ErrDomainNotFound
+	}
+
+	var results []*models.CheckResult
+	for _, result := range m.checkResults[domainID] {
+		if (from.IsZero() || !result.CheckedAt.Before(from)) && (to.IsZero() || result.CheckedAt.Before(to)) &&
+			(after == nil || after.Follows(result)) {
+			results = append(results, result)
+		}
+	}
+	slices.SortFunc(results, func(a, b *models.CheckResult) int {
+		return models.CompareCheckResults(b, a)
+	})
+
+	return models.NewCheckResultCursorPage(results[:min(len(results), limit+1)], limit), nil
+}
+
+func (m *MockStorage) LatestCheckResult(domainID uuid.UUID) (*models.CheckResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "LatestCheckResult")
+
+	if _, exists := m.domains[domainID]; !exists {
+		return nil, storage.ErrDomainNotFound
+	}
+
+	var latest *models.CheckResult
+	for _, result := range m.checkResults[domainID] {
+		if latest == nil || !result.CheckedAt.Before(latest.CheckedAt) {
+			latest = result
+		}
+	}
+	if latest == nil {
+		return nil, storage.ErrCheckResultNotFound
+	}
+	return latest, nil
+}
+
+func (m *MockStorage) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callHistory = append(m.callHistory, "Close")
+	return nil
+}
+
 func (m *MockStorage) GetCallHistory() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	return m.callHistory
 }
 
 func (m *MockStorage) ClearCallHistory() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.callHistory = []string{}
 }
 
 func (m *MockStorage) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.domains = make(map[uuid.UUID]*models.Domain)
+	m.dnsChanges = make(map[uuid.UUID][]*models.DNSChangeEvent)
+	m.checkResults = make(map[uuid.UUID][]*models.CheckResult)
 	m.createDomainShouldError = false
 	m.getDomainShouldError = false
 	m.listDomainsShouldError = false
 	m.updateDomainShouldError = false
 	m.deleteDomainShouldError = false
-	m.ClearCallHistory()
+	m.callHistory = []string{}
 }
 
 func (m *MockStorage) SetCreateDomainError(shouldError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.createDomainShouldError = shouldError
 }
 
 func (m *MockStorage) SetGetDomainError(shouldError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.getDomainShouldError = shouldError
 }
 
 func (m *MockStorage) SetListDomainsError(shouldError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.listDomainsShouldError = shouldError
 }
 
 func (m *MockStorage) SetUpdateDomainError(shouldError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.updateDomainShouldError = shouldError
 }
 
 func (m *MockStorage) SetDeleteDomainError(shouldError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.deleteDomainShouldError = shouldError
 }
+
+// As variantes com Context falham com o erro de ctx quando ele já terminou e,
+// caso contrário, se comportam como as versões sem Context
+
+func (m *MockStorage) CreateDomainContext(ctx context.Context, domain *models.Domain) (uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return uuid.Nil, err
+	}
+	return m.CreateDomain(domain)
+}
+
+func (m *MockStorage) GetDomainContext(ctx context.Context, id uuid.UUID) (*models.Domain, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetDomain(id)
+}
+
+func (m *MockStorage) ListDomainsContext(ctx context.Context, opts models.ListOptions) (*models.DomainPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDomains(opts)
+}
+
+func (m *MockStorage) ListDomainsCursorContext(ctx context.Context, opts models.CursorOptions) (*models.DomainCursorPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDomainsCursor(opts)
+}
+
+func (m *MockStorage) UpdateDomainContext(ctx context.Context, domain *models.Domain) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.UpdateDomain(domain)
+}
+
+func (m *MockStorage) DeleteDomainContext(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.DeleteDomain(id)
+}
+
+func (m *MockStorage) SaveDNSChangeContext(ctx context.Context, event *models.DNSChangeEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.SaveDNSChange(event)
+}
+
+func (m *MockStorage) ListDNSChangesContext(ctx context.Context, domainID uuid.UUID) ([]*models.DNSChangeEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListDNSChanges(domainID)
+}
+
+func (m *MockStorage) LatestDNSChangeContext(ctx context.Context, domainID uuid.UUID) (*models.DNSChangeEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LatestDNSChange(domainID)
+}
+
+func (m *MockStorage) SaveCheckResultContext(ctx context.Context, result *models.CheckResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.SaveCheckResult(result)
+}
+
+func (m *MockStorage) ListCheckResultsContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, page, pageSize int) ([]*models.CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListCheckResults(domainID, from, to, page, pageSize)
+}
+
+func (m *MockStorage) ListCheckResultsCursorContext(ctx context.Context, domainID uuid.UUID, from, to time.Time, cursor string, limit int) (*models.CheckResultCursorPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListCheckResultsCursor(domainID, from, to, cursor, limit)
+}
+
+func (m *MockStorage) LatestCheckResultContext(ctx context.Context, domainID uuid.UUID) (*models.CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.LatestCheckResult(domainID)
+}
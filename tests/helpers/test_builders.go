@@ -15,11 +15,13 @@ type TestDomainBuilder struct {
 }
 
 func NewTestDomainBuilder() *TestDomainBuilder {
+	id := uuid.New()
 	return &TestDomainBuilder{
 		domain: &models.Domain{
-			ID:   uuid.New(),
+			ID:   id,
 			Name: "Test Domain",
-			URL:  "test.example.com",
+			// A URL é única por domínio, como exigem os storages
+			URL: fmt.Sprintf("test-%s.example.com", id),
 		},
 	}
 }
@@ -39,6 +41,21 @@ func (b *TestDomainBuilder) WithURL(url string) *TestDomainBuilder {
 	return b
 }
 
+func (b *TestDomainBuilder) WithInterval(interval int) *TestDomainBuilder {
+	b.domain.Interval = interval
+	return b
+}
+
+func (b *TestDomainBuilder) WithTags(tags ...string) *TestDomainBuilder {
+	b.domain.Tags = tags
+	return b
+}
+
+func (b *TestDomainBuilder) WithAssertions(assertions *models.Assertions) *TestDomainBuilder {
+	b.domain.Assertions = assertions
+	return b
+}
+
 func (b *TestDomainBuilder) Build() *models.Domain {
 	return b.domain
 }
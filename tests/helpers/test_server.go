@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// NewTestHTTPServer - Servidor HTTP local para testes de integração do checker.
+// O servidor é encerrado automaticamente ao final do teste.
+func NewTestHTTPServer(tb testing.TB, handler http.Handler) *httptest.Server {
+	tb.Helper()
+
+	server := httptest.NewServer(handler)
+	tb.Cleanup(server.Close)
+	return server
+}
+
+// ServerPort retorna a porta em que o servidor de teste está escutando
+func ServerPort(tb testing.TB, server *httptest.Server) string {
+	tb.Helper()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		tb.Fatalf("Invalid test server URL: %v", err)
+	}
+
+	_, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		tb.Fatalf("Invalid test server host: %v", err)
+	}
+	return port
+}
+
+// ServerURLFor monta uma URL com o host informado apontando para a porta do
+// servidor de teste. O host deve ser resolvido para 127.0.0.1 pelo MockDNS.
+func ServerURLFor(tb testing.TB, server *httptest.Server, host string) string {
+	tb.Helper()
+
+	u, _ := url.Parse(server.URL)
+	u.Host = net.JoinHostPort(host, ServerPort(tb, server))
+	return u.String()
+}
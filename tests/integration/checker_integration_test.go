@@ -2,19 +2,26 @@ package integration
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/luizhreis/domain-watcher/internal/checker"
+	"github.com/luizhreis/domain-watcher/internal/models"
 	"github.com/luizhreis/domain-watcher/tests/helpers"
 )
 
 // TestCheckerIntegration - Testes black-box de integração
 func TestCheckerIntegration(t *testing.T) {
+	server := helpers.NewTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "integration")
+		_, _ = w.Write([]byte("ok"))
+	}))
+
 	t.Run("End-to-End Success Flow", func(t *testing.T) {
 		// Arrange - integração real entre componentes
 		mockDNS := helpers.NewMockDNS()
-		expectedIP := "203.0.113.1"
+		expectedIP := "127.0.0.1"
 		testDomainID := uuid.New()
 
 		mockDNS.SetResolveFunc(func(domain string) (string, error) {
@@ -22,7 +29,7 @@ func TestCheckerIntegration(t *testing.T) {
 			case "production.example.com":
 				return expectedIP, nil
 			case "staging.example.com":
-				return "203.0.113.2", nil
+				return "127.0.0.2", nil
 			default:
 				return "", errors.New("domain not found")
 			}
@@ -32,7 +39,7 @@ func TestCheckerIntegration(t *testing.T) {
 		domain := helpers.NewTestDomainBuilder().
 			WithID(testDomainID).
 			WithName("Production Domain").
-			WithURL("production.example.com").
+			WithURL(helpers.ServerURLFor(t, server, "production.example.com")).
 			Build()
 
 		// Act - comportamento de ponta a ponta
@@ -51,6 +58,10 @@ func TestCheckerIntegration(t *testing.T) {
 			HasNoError().
 			HasValidTimestamp()
 
+		if result.Server != "integration" {
+			t.Errorf("Expected Server header 'integration', got %s", result.Server)
+		}
+
 		if !matcher.IsValid() {
 			for _, err := range matcher.GetErrors() {
 				t.Error(err)
@@ -84,17 +95,25 @@ func TestCheckerIntegration(t *testing.T) {
 		// Act
 		result, err := checkerInstance.CheckDomain(domain)
 
-		// Assert - comportamento de erro integrado
-		if err == nil {
-			t.Error("Expected error but got none")
+		// Assert - falha de DNS é registrada como resultado com falha
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		if err != dnsError {
-			t.Errorf("Expected specific DNS error, got %v", err)
+		if result == nil {
+			t.Fatal("Expected failed result, got nil")
 		}
 
-		if result != nil {
-			t.Error("Result should be nil on error")
+		if result.DomainID != domain.ID {
+			t.Errorf("Expected DomainID %v, got %v", domain.ID, result.DomainID)
+		}
+
+		if result.Error != dnsError.Error() {
+			t.Errorf("Expected DNS error %q, got %q", dnsError.Error(), result.Error)
+		}
+
+		if result.ErrorKind != models.ErrorKindDNS {
+			t.Errorf("Expected ErrorKind %q, got %q", models.ErrorKindDNS, result.ErrorKind)
 		}
 
 		// Verifica que DNS foi chamado mesmo com erro
@@ -103,18 +122,51 @@ func TestCheckerIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("End-to-End HTTP Failure Flow", func(t *testing.T) {
+		// Arrange - DNS resolve, mas o servidor HTTP responde com erro
+		failing := helpers.NewTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+
+		mockDNS := helpers.NewMockDNS()
+		mockDNS.SetResolveFunc(func(domain string) (string, error) {
+			return "127.0.0.1", nil
+		})
+
+		checkerInstance := checker.NewChecker(mockDNS)
+		domain := helpers.NewTestDomainBuilder().
+			WithURL(helpers.ServerURLFor(t, failing, "degraded.example.com")).
+			Build()
+
+		// Act
+		result, err := checkerInstance.CheckDomain(domain)
+
+		// Assert - o status real é registrado no resultado
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		matcher := helpers.NewTestCheckResultMatcher(result).
+			HasDomainID(domain.ID).
+			HasStatusCode(http.StatusServiceUnavailable).
+			HasValidTimestamp()
+
+		if !matcher.IsValid() {
+			for _, err := range matcher.GetErrors() {
+				t.Error(err)
+			}
+		}
+	})
+
 	t.Run("Multiple Domain Integration", func(t *testing.T) {
 		// Arrange - teste de múltiplos domínios
 		mockDNS := helpers.NewMockDNS()
 
 		mockDNS.SetResolveFunc(func(domain string) (string, error) {
 			switch domain {
-			case "api.example.com":
-				return "10.0.0.1", nil
-			case "web.example.com":
-				return "10.0.0.2", nil
-			case "cdn.example.com":
-				return "10.0.0.3", nil
+			case "api.example.com", "web.example.com", "cdn.example.com":
+				// Todos apontam para o servidor de teste local
+				return "127.0.0.1", nil
 			default:
 				return "", errors.New("unknown domain")
 			}
@@ -128,12 +180,12 @@ func TestCheckerIntegration(t *testing.T) {
 		domainID3 := uuid.New()
 
 		domains := []*helpers.TestDomainBuilder{
-			helpers.NewTestDomainBuilder().WithID(domainID1).WithURL("api.example.com"),
-			helpers.NewTestDomainBuilder().WithID(domainID2).WithURL("web.example.com"),
-			helpers.NewTestDomainBuilder().WithID(domainID3).WithURL("cdn.example.com"),
+			helpers.NewTestDomainBuilder().WithID(domainID1).WithURL(helpers.ServerURLFor(t, server, "api.example.com")),
+			helpers.NewTestDomainBuilder().WithID(domainID2).WithURL(helpers.ServerURLFor(t, server, "web.example.com")),
+			helpers.NewTestDomainBuilder().WithID(domainID3).WithURL(helpers.ServerURLFor(t, server, "cdn.example.com")),
 		}
 
-		expectedIPs := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+		expectedIPs := []string{"127.0.0.1", "127.0.0.1", "127.0.0.1"}
 
 		// Act - verifica integração com múltiplas chamadas
 		for i, domainBuilder := range domains {
@@ -174,14 +226,16 @@ func TestCheckerIntegration(t *testing.T) {
 
 // BenchmarkCheckerIntegration - benchmark de integração
 func BenchmarkCheckerIntegration(b *testing.B) {
+	server := helpers.NewTestHTTPServer(b, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
 	mockDNS := helpers.NewMockDNS()
 	mockDNS.SetResolveFunc(func(domain string) (string, error) {
-		return "192.168.1.1", nil
+		return "127.0.0.1", nil
 	})
 
 	checkerInstance := checker.NewChecker(mockDNS)
 	domain := helpers.NewTestDomainBuilder().
-		WithURL("benchmark.example.com").
+		WithURL(helpers.ServerURLFor(b, server, "benchmark.example.com")).
 		Build()
 
 	b.ResetTimer()